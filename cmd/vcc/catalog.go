@@ -0,0 +1,290 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"strings"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+)
+
+// catalogFlags are accepted by the commands which query a product
+type catalogFlags struct {
+	dlgType      string
+	majorVersion string
+}
+
+func (c *catalogFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.dlgType, "dlg-type", sdk.DefaultDlgType, "download type: PRODUCT_BINARY, DRIVERS_TOOLS, OPEN_SOURCE or CUSTOM_ISO")
+	fs.StringVar(&c.majorVersion, "major-version", "", "only include this major version, e.g. 8_0")
+}
+
+type productRecord struct {
+	Slug               string `json:"slug" yaml:"slug"`
+	Name               string `json:"name" yaml:"name"`
+	Category           string `json:"category" yaml:"category"`
+	LatestMajorVersion string `json:"latestMajorVersion" yaml:"latestMajorVersion"`
+}
+
+func runProducts(ctx context.Context, env *environment, args []string) (err error) {
+	var common commonFlags
+	fs := newFlagSet(env, "products", &common)
+	if _, err = parseFlags(fs, args, 0); err != nil {
+		return
+	}
+	if err = validateFormat(common.output); err != nil {
+		return
+	}
+
+	var client *sdk.Client
+	if client, err = common.newClient(ctx, false); err != nil {
+		return
+	}
+
+	var productMap map[string]sdk.ProductDetails
+	if productMap, err = client.GetProductsMapCtx(ctx); err != nil {
+		return
+	}
+
+	records := make([]productRecord, 0, len(productMap))
+	for slug, details := range productMap {
+		records = append(records, productRecord{
+			Slug:               slug,
+			Name:               details.DisplayName,
+			Category:           details.Category,
+			LatestMajorVersion: details.LatestMajorVersion,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Slug < records[j].Slug })
+
+	out := table{headers: []string{"slug", "name", "category", "latest major version"}, records: records}
+	for _, record := range records {
+		out.rows = append(out.rows, []string{record.Slug, record.Name, record.Category, record.LatestMajorVersion})
+	}
+	return out.write(env.stdout, common.output)
+}
+
+type subProductRecord struct {
+	Code          string   `json:"code" yaml:"code"`
+	Name          string   `json:"name" yaml:"name"`
+	MajorVersions []string `json:"majorVersions" yaml:"majorVersions"`
+}
+
+func runSubProducts(ctx context.Context, env *environment, args []string) (err error) {
+	var common commonFlags
+	var catalog catalogFlags
+	fs := newFlagSet(env, "subproducts", &common)
+	catalog.register(fs)
+	var positional []string
+	if positional, err = parseFlags(fs, args, 1); err != nil {
+		return
+	}
+	if err = validateFormat(common.output); err != nil {
+		return
+	}
+
+	var client *sdk.Client
+	if client, err = common.newClient(ctx, false); err != nil {
+		return
+	}
+
+	var subProducts []sdk.SubProductDetails
+	subProducts, err = client.GetSubProductsSliceCtx(ctx, positional[0], catalog.dlgType, catalog.majorVersion)
+	if err != nil {
+		return
+	}
+
+	records := make([]subProductRecord, 0, len(subProducts))
+	for _, subProduct := range subProducts {
+		majorVersions := make([]string, 0, len(subProduct.DlgListByVersion))
+		for majorVersion := range subProduct.DlgListByVersion {
+			majorVersions = append(majorVersions, majorVersion)
+		}
+		sort.Slice(majorVersions, func(i, j int) bool {
+			return sdk.CompareVersions(majorVersions[i], majorVersions[j]) > 0
+		})
+		records = append(records, subProductRecord{
+			Code:          subProduct.ProductCode,
+			Name:          subProduct.ProductName,
+			MajorVersions: majorVersions,
+		})
+	}
+
+	out := table{headers: []string{"code", "name", "major versions"}, records: records}
+	for _, record := range records {
+		out.rows = append(out.rows, []string{record.Code, record.Name, strings.Join(record.MajorVersions, ",")})
+	}
+	return out.write(env.stdout, common.output)
+}
+
+type versionRecord struct {
+	Version       string `json:"version" yaml:"version"`
+	MajorVersion  string `json:"majorVersion" yaml:"majorVersion"`
+	DownloadGroup string `json:"downloadGroup" yaml:"downloadGroup"`
+}
+
+func runVersions(ctx context.Context, env *environment, args []string) (err error) {
+	var common commonFlags
+	var catalog catalogFlags
+	fs := newFlagSet(env, "versions", &common)
+	catalog.register(fs)
+	var positional []string
+	if positional, err = parseFlags(fs, args, 2); err != nil {
+		return
+	}
+	if err = validateFormat(common.output); err != nil {
+		return
+	}
+
+	var client *sdk.Client
+	if client, err = common.newClient(ctx, false); err != nil {
+		return
+	}
+
+	var versionMap map[string]sdk.APIVersions
+	versionMap, err = client.GetVersionMapCtx(ctx, positional[0], positional[1], catalog.dlgType)
+	if err != nil {
+		return
+	}
+
+	records := make([]versionRecord, 0, len(versionMap))
+	for _, version := range sortedVersions(filterMajorVersion(versionMap, catalog.majorVersion)) {
+		records = append(records, versionRecord{
+			Version:       version,
+			MajorVersion:  versionMap[version].MajorVersion,
+			DownloadGroup: versionMap[version].Code,
+		})
+	}
+
+	out := table{headers: []string{"version", "major version", "download group"}, records: records}
+	for _, record := range records {
+		out.rows = append(out.rows, []string{record.Version, record.MajorVersion, record.DownloadGroup})
+	}
+	return out.write(env.stdout, common.output)
+}
+
+type fileRecord struct {
+	FileName    string `json:"fileName" yaml:"fileName"`
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Build       string `json:"build" yaml:"build"`
+	ReleaseDate string `json:"releaseDate" yaml:"releaseDate"`
+	FileSize    string `json:"fileSize" yaml:"fileSize"`
+	Sha256      string `json:"sha256" yaml:"sha256"`
+	UUID        string `json:"uuid" yaml:"uuid"`
+}
+
+func runFiles(ctx context.Context, env *environment, args []string) (err error) {
+	var common commonFlags
+	var catalog catalogFlags
+	fs := newFlagSet(env, "files", &common)
+	catalog.register(fs)
+	var positional []string
+	if positional, err = parseFlags(fs, args, 3); err != nil {
+		return
+	}
+	if err = validateFormat(common.output); err != nil {
+		return
+	}
+
+	var client *sdk.Client
+	if client, err = common.newClient(ctx, false); err != nil {
+		return
+	}
+
+	var apiVersions sdk.APIVersions
+	var productID string
+	apiVersions, productID, err = resolveVersion(ctx, client, positional[0], positional[1], positional[2], catalog)
+	if err != nil {
+		return
+	}
+
+	var dlgDetails sdk.DlgDetails
+	if dlgDetails, err = client.GetDlgDetailsCtx(ctx, apiVersions.Code, productID); err != nil {
+		return
+	}
+
+	records := make([]fileRecord, 0, len(dlgDetails.DownloadDetails))
+	for _, details := range dlgDetails.DownloadDetails {
+		if details.FileName == "" {
+			continue
+		}
+		records = append(records, fileRecord{
+			FileName:    details.FileName,
+			Title:       details.Title,
+			Version:     apiVersions.MinorVersion,
+			Build:       details.Build,
+			ReleaseDate: details.ReleaseDate,
+			FileSize:    details.FileSize,
+			Sha256:      details.Sha256Checksum,
+			UUID:        details.UUID,
+		})
+	}
+
+	out := table{headers: []string{"file name", "version", "build", "release date", "size", "sha256"}, records: records}
+	for _, record := range records {
+		out.rows = append(out.rows, []string{record.FileName, record.Version, record.Build, record.ReleaseDate, record.FileSize, record.Sha256})
+	}
+	return out.write(env.stdout, common.output)
+}
+
+// resolveVersion finds a version, which may be a glob, and the product ID of its download group.
+// With a major version set, only versions within it are considered.
+func resolveVersion(ctx context.Context, client *sdk.Client, slug, subProduct, version string, catalog catalogFlags) (apiVersions sdk.APIVersions, productID string, err error) {
+	if catalog.majorVersion == "" {
+		productID, apiVersions, err = client.GetDlgProductCtx(ctx, slug, subProduct, version, catalog.dlgType)
+		return
+	}
+
+	var versionMap map[string]sdk.APIVersions
+	versionMap, err = client.GetVersionMapCtx(ctx, slug, subProduct, catalog.dlgType)
+	if err != nil {
+		return
+	}
+	versionMap = filterMajorVersion(versionMap, catalog.majorVersion)
+
+	if strings.Contains(version, "*") {
+		if version, err = client.FindVersionFromGlob(version, versionMap); err != nil {
+			return
+		}
+	}
+	var ok bool
+	if apiVersions, ok = versionMap[version]; !ok {
+		err = sdk.ErrorInvalidVersion
+		return
+	}
+	apiVersions.MinorVersion = version
+
+	var dlgList sdk.DlgList
+	dlgList, err = client.GetSubProductDetailsCtx(ctx, slug, subProduct, catalog.majorVersion, catalog.dlgType)
+	productID = dlgList.ProductID
+	return
+}
+
+func filterMajorVersion(versionMap map[string]sdk.APIVersions, majorVersion string) map[string]sdk.APIVersions {
+	if majorVersion == "" {
+		return versionMap
+	}
+	filtered := make(map[string]sdk.APIVersions)
+	for version, apiVersions := range versionMap {
+		if apiVersions.MajorVersion == majorVersion {
+			filtered[version] = apiVersions
+		}
+	}
+	return filtered
+}
+
+// Newest first, matching GetVersionSlice
+func sortedVersions(versionMap map[string]sdk.APIVersions) (versions []string) {
+	for version := range versionMap {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return sdk.CompareVersions(versions[i], versions[j]) > 0
+	})
+	return
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+)
+
+func runDownload(ctx context.Context, env *environment, args []string) (err error) {
+	var common commonFlags
+	var catalog catalogFlags
+	fs := newFlagSet(env, "download", &common)
+	catalog.register(fs)
+	acceptEula := fs.Bool("accept-eula", false, "accept the EULA of the download group, after reviewing it")
+	dest := fs.String("dest", ".", "directory files are downloaded to")
+	noProgress := fs.Bool("no-progress", false, "do not show progress bars, which are only shown on a terminal")
+	var positional []string
+	if positional, err = parseFlags(fs, args, 4); err != nil {
+		return
+	}
+	slug, subProduct, version, fileGlob := positional[0], positional[1], positional[2], positional[3]
+
+	var client *sdk.Client
+	if client, err = common.newClient(ctx, true); err != nil {
+		return
+	}
+
+	var apiVersions sdk.APIVersions
+	var productID string
+	if apiVersions, productID, err = resolveVersion(ctx, client, slug, subProduct, version, catalog); err != nil {
+		return
+	}
+
+	var found sdk.FoundDownload
+	if found, err = client.FindDlgDetailsCtx(ctx, apiVersions.Code, productID, fileGlob); err != nil {
+		if errors.Is(err, sdk.ErrorNoMatchingFiles) {
+			err = fmt.Errorf("%w: %s in %s %s", err, fileGlob, subProduct, apiVersions.MinorVersion)
+		}
+		return
+	}
+	if !found.EligibleToDownload {
+		return fmt.Errorf("%w: %s %s", sdk.ErrorNotEntitled, subProduct, apiVersions.MinorVersion)
+	}
+
+	if !found.EulaAccepted {
+		var eulaURL string
+		if eulaURL, err = client.FetchEulaUrlCtx(ctx, apiVersions.Code, productID); err != nil {
+			return
+		}
+		fmt.Fprintf(env.stderr, "EULA: %s\n", eulaURL)
+		if !*acceptEula {
+			return fmt.Errorf("%w: review the EULA and run again with --accept-eula", sdk.ErrorEulaUnaccepted)
+		}
+		if err = client.AcceptEulaCtx(ctx, apiVersions.Code, productID); err != nil {
+			return
+		}
+		fmt.Fprintln(env.stderr, "EULA accepted")
+	}
+
+	var payloads []sdk.DownloadPayload
+	payloads, err = client.GenerateDownloadPayloadCtx(ctx, slug, subProduct, apiVersions.MinorVersion, fileGlob, catalog.dlgType, false)
+	if err != nil {
+		return
+	}
+
+	details := make(map[string]sdk.DownloadDetails)
+	for _, file := range found.DownloadDetails {
+		details[file.UUID] = file
+	}
+
+	if err = os.MkdirAll(*dest, 0755); err != nil {
+		return
+	}
+
+	showProgress := !*noProgress && isTerminal(env.stderr)
+	var errs []error
+	for _, payload := range payloads {
+		file := details[payload.UUId]
+		var path string
+		if path, err = downloadFile(ctx, env, client, payload, file, *dest, showProgress); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.FileName, err))
+			continue
+		}
+		fmt.Fprintln(env.stdout, path)
+	}
+	return errors.Join(errs...)
+}
+
+func downloadFile(ctx context.Context, env *environment, client *sdk.Client, payload sdk.DownloadPayload, file sdk.DownloadDetails, dest string, showProgress bool) (path string, err error) {
+	var authorizedDownload sdk.AuthorizedDownload
+	if authorizedDownload, err = client.FetchDownloadLinkCtx(ctx, payload); err != nil {
+		return
+	}
+
+	opts := sdk.DownloadOptions{Checksums: sdk.ChecksumsFromDetails(file)}
+	if showProgress {
+		bar := newProgressBar(env.stderr, authorizedDownload.FileName)
+		opts.Progress = bar.update
+		defer bar.done()
+	}
+
+	return client.Download(ctx, authorizedDownload, dest, opts)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+const darwinTools = "VMware-Tools-darwin-11.1.1-16303738.tar.gz"
+
+func fixtureContent(t *testing.T, fileName string) string {
+	t.Helper()
+
+	for _, file := range fakecc.DefaultFixtures().Files {
+		if file.FileName == fileName {
+			return file.Content
+		}
+	}
+	t.Fatalf("no fixture for %s", fileName)
+	return ""
+}
+
+func TestDownload(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := t.TempDir()
+
+	stdout, stderr, code := runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-*", "--dest", dir)
+	require.Equal(t, exitOK, code, stderr)
+
+	paths := strings.Fields(stdout)
+	require.Len(t, paths, 3)
+	content, err := os.ReadFile(filepath.Join(dir, darwinTools))
+	require.Nil(t, err)
+	assert.Equal(t, fixtureContent(t, darwinTools), string(content))
+}
+
+func TestDownloadMissingDest(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	stdout, stderr, code := runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-*", "--dest", dir)
+	require.Equal(t, exitOK, code, stderr)
+
+	paths := strings.Fields(stdout)
+	require.Len(t, paths, 3)
+	for _, path := range paths {
+		assert.Equal(t, dir, filepath.Dir(path))
+		assert.FileExists(t, path)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := t.TempDir()
+
+	content := fixtureContent(t, darwinTools)
+	path := filepath.Join(dir, darwinTools)
+	require.Nil(t, os.WriteFile(path+".partial", []byte(content[:len(content)/2]), 0644))
+
+	_, stderr, code := runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-darwin-*", "--dest", dir)
+	require.Equal(t, exitOK, code, stderr)
+
+	downloaded, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, content, string(downloaded))
+	assert.NoFileExists(t, path+".partial")
+}
+
+func TestDownloadEula(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := t.TempDir()
+	args := []string{"download", "vmware_tools", "vmtools", "11.1.0", "VMware-Tools-darwin-*", "--dest", dir}
+
+	_, stderr, code := runVcc(t, args...)
+	assert.Equal(t, exitEulaUnaccepted, code)
+	assert.Contains(t, stderr, "EULA: https://www.vmware.com/download/eula/vmtools1110.html")
+	assert.Contains(t, stderr, "--accept-eula")
+	assert.NoFileExists(t, filepath.Join(dir, "VMware-Tools-darwin-11.1.0-16036546.tar.gz"))
+
+	_, stderr, code = runVcc(t, append(args, "--accept-eula")...)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stderr, "EULA accepted")
+	assert.FileExists(t, filepath.Join(dir, "VMware-Tools-darwin-11.1.0-16036546.tar.gz"))
+}
+
+func TestDownloadExitCodes(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := t.TempDir()
+
+	_, stderr, code := runVcc(t, "download", "vmware_vsphere", "esxi", "7.0U3", "*.iso", "--dest", dir)
+	assert.Equal(t, exitNotEntitled, code, stderr)
+
+	_, stderr, code = runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "*.exe", "--dest", dir)
+	assert.Equal(t, exitNoMatchingFiles, code, stderr)
+
+	setCredentials(t, "", "")
+	_, stderr, code = runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "*.zip", "--dest", dir)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "VMWCC_USER")
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	fixtures := fakecc.DefaultFixtures()
+	for uuid, file := range fixtures.Files {
+		if file.FileName == darwinTools {
+			file.Content = "corrupted"
+			fixtures.Files[uuid] = file
+		}
+	}
+	newFakeServerWithFixtures(t, fixtures)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+	dir := t.TempDir()
+
+	stdout, stderr, code := runVcc(t, "download", "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-*", "--dest", dir)
+	assert.Equal(t, exitChecksumMismatch, code)
+	assert.Contains(t, stderr, darwinTools)
+	// The other files are still downloaded
+	assert.Len(t, strings.Fields(stdout), 2)
+	assert.NoFileExists(t, filepath.Join(dir, darwinTools))
+}
+
+func TestProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Now()
+	bar := newProgressBar(&out, "file.iso")
+	bar.now = func() time.Time { return now }
+
+	bar.update(512, 2048)
+	bar.update(1024, 2048)
+	assert.Equal(t, "\rfile.iso [=======                       ]  25% 512 B/2.0 KiB", out.String())
+
+	bar.done()
+	assert.True(t, strings.HasSuffix(out.String(), "\rfile.iso [===============               ]  50% 1.0 KiB/2.0 KiB\n"))
+
+	out.Reset()
+	bar = newProgressBar(&out, "file.iso")
+	bar.update(3*1024*1024, -1)
+	assert.Equal(t, "\rfile.iso 3.0 MiB", out.String())
+}
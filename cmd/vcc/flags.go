@@ -0,0 +1,136 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+)
+
+// Environment variables read by vcc, in addition to VMWCC_USER, VMWCC_PASS and VMWCC_SESSION_KEY
+const (
+	baseURLEnv = "VMWCC_BASE_URL"
+	authURLEnv = "VMWCC_AUTH_URL"
+)
+
+// commonFlags are accepted by every command
+type commonFlags struct {
+	output string
+
+	user             string
+	netrc            bool
+	credentialHelper string
+	sessionFile      string
+	cacheDir         string
+
+	baseURL string
+	authURL string
+}
+
+func newFlagSet(env *environment, name string, common *commonFlags) *flag.FlagSet {
+	cmd := commands[name]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Usage: vcc %s\n\n%s\n\nFlags:\n", cmd.usage, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	home, _ := os.UserHomeDir()
+	cacheDir, _ := os.UserCacheDir()
+	if cacheDir != "" {
+		cacheDir = filepath.Join(cacheDir, "vcc")
+	}
+
+	fs.StringVar(&common.output, "output", formatTable, "output format: table, json, yaml or csv")
+	fs.StringVar(&common.user, "user", "", "read the password of this user from the system keyring instead of VMWCC_USER and VMWCC_PASS")
+	fs.BoolVar(&common.netrc, "netrc", false, "read credentials from ~/.netrc")
+	fs.StringVar(&common.credentialHelper, "credential-helper", "", "command printing credentials using the git credential helper protocol")
+	fs.StringVar(&common.sessionFile, "session-file", filepath.Join(home, ".vmware.session"), "file the session is kept in, encrypted with VMWCC_SESSION_KEY. Empty disables")
+	fs.StringVar(&common.cacheDir, "cache-dir", cacheDir, "directory catalog responses are cached in. Empty disables")
+	fs.StringVar(&common.baseURL, "base-url", os.Getenv(baseURLEnv), "override the Customer Connect URL")
+	fs.StringVar(&common.authURL, "auth-url", os.Getenv(authURLEnv), "override the credential submission URL")
+	return fs
+}
+
+// parseFlags parses args, allowing flags to follow the positional arguments,
+// and checks the number of positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string, nargs int) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			if !errors.Is(err, flag.ErrHelp) {
+				err = usageError{msg: err.Error()}
+			}
+			return
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != nargs {
+		fs.Usage()
+		err = usageErrorf("expected %d arguments, got %d", nargs, len(positional))
+	}
+	return
+}
+
+func (c *commonFlags) clientOptions() (opts sdk.ClientOptions, err error) {
+	opts.Endpoints = sdk.NewEndpoints(c.baseURL, c.authURL)
+
+	if c.cacheDir != "" {
+		if opts.Cache, err = sdk.NewFileCache(c.cacheDir); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (c *commonFlags) credentials() sdk.CredentialProvider {
+	switch {
+	case c.credentialHelper != "":
+		return sdk.ExecCredentials{Command: strings.Fields(c.credentialHelper)}
+	case c.netrc:
+		return sdk.NetrcCredentials{}
+	case c.user != "":
+		home, _ := os.UserHomeDir()
+		keyring := sdk.NewKeyring(filepath.Join(home, ".vmware.keyring"))
+		return sdk.KeyringCredentials{Keyring: keyring, Username: c.user}
+	}
+	return sdk.EnvCredentials{}
+}
+
+// newClient logs in when credentials are available. Otherwise the client can only use the
+// public endpoints, unless login is required.
+func (c *commonFlags) newClient(ctx context.Context, requireLogin bool) (client *sdk.Client, err error) {
+	var opts sdk.ClientOptions
+	if opts, err = c.clientOptions(); err != nil {
+		return
+	}
+
+	provider := c.credentials()
+	var username, password string
+	username, password, err = provider.Credentials(ctx)
+	if errors.Is(err, sdk.ErrorMissingCredentials) && !requireLogin {
+		return sdk.NewClient(opts), nil
+	} else if err != nil {
+		return
+	}
+
+	opts.Credentials = provider
+	if c.sessionFile != "" && os.Getenv(sdk.DefaultSessionKeyEnv) != "" {
+		opts.SessionStore = sdk.EncryptedFileSessionStore{Path: c.sessionFile}
+	}
+	return sdk.LoginCtx(ctx, username, password, nil, opts)
+}
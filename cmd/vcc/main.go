@@ -0,0 +1,144 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+// Command vcc browses the VMware Customer Connect catalog.
+//
+//	vcc products
+//	vcc subproducts <slug>
+//	vcc versions <slug> <subproduct>
+//	vcc files <slug> <subproduct> <version>
+//	vcc download <slug> <subproduct> <version> <fileGlob>
+//
+// Run vcc <command> -h for the flags of a command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+)
+
+// Exit codes. Failures which need the user to act have their own code, so scripts can tell them apart.
+const (
+	exitOK               = 0
+	exitError            = 1
+	exitUsage            = 2
+	exitNotEntitled      = 3
+	exitEulaUnaccepted   = 4
+	exitNoMatchingFiles  = 5
+	exitChecksumMismatch = 6
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, env *environment, args []string) error
+}
+
+var commands map[string]command
+
+// Set in init, as the commands refer to the table for their usage
+func init() {
+	commands = map[string]command{
+		"products":    {"products [flags]", "List products and their slugs", runProducts},
+		"subproducts": {"subproducts [flags] <slug>", "List the sub-products of a product", runSubProducts},
+		"versions":    {"versions [flags] <slug> <subproduct>", "List the versions of a sub-product, newest first", runVersions},
+		"files":       {"files [flags] <slug> <subproduct> <version>", "List the files of a version", runFiles},
+		"download":    {"download [flags] <slug> <subproduct> <version> <fileGlob>", "Download the files of a version matching a glob", runDownload},
+	}
+}
+
+// environment holds what commands need from the process, so they can be run from tests
+type environment struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, &environment{stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, env *environment, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(env.stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.stderr, "vcc: unknown command %q\n\n", args[0])
+		printUsage(env.stderr)
+		return exitUsage
+	}
+
+	err := cmd.run(ctx, env, args[1:])
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, new(usageError)):
+		fmt.Fprintf(env.stderr, "vcc %s: %v\n", args[0], err)
+		return exitUsage
+	default:
+		fmt.Fprintf(env.stderr, "vcc %s: %v\n", args[0], err)
+		return exitCode(err)
+	}
+}
+
+// exitCode maps an error to the exit status of the process. When several downloads
+// failed, the first class in the order below is reported.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, sdk.ErrorNotEntitled):
+		return exitNotEntitled
+	case errors.Is(err, sdk.ErrorEulaUnaccepted):
+		return exitEulaUnaccepted
+	case errors.Is(err, sdk.ErrorNoMatchingFiles):
+		return exitNoMatchingFiles
+	case errors.Is(err, sdk.ErrorChecksumMismatch):
+		return exitChecksumMismatch
+	}
+	return exitError
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: vcc <command> [flags] [args]")
+	fmt.Fprintln(w, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(w, "\nRun vcc <command> -h for the flags of a command.")
+}
+
+// usageError is returned for invalid arguments, exiting with exitUsage
+type usageError struct {
+	msg string
+}
+
+func (u usageError) Error() string {
+	return u.msg
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
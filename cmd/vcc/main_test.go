@@ -0,0 +1,157 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+// newFakeServer starts a fake Customer Connect server, which vcc is pointed at through the environment
+func newFakeServer(t *testing.T) *fakecc.Server {
+	t.Helper()
+	return newFakeServerWithFixtures(t, nil)
+}
+
+func newFakeServerWithFixtures(t *testing.T, fixtures *fakecc.Fixtures) *fakecc.Server {
+	t.Helper()
+
+	srv := fakecc.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	t.Setenv(baseURLEnv, srv.URL)
+	t.Setenv(authURLEnv, srv.AuthURL())
+	t.Setenv("VMWCC_USER", "")
+	t.Setenv("VMWCC_PASS", "")
+	t.Setenv("VMWCC_SESSION_KEY", "")
+	return srv
+}
+
+func setCredentials(t *testing.T, username, password string) {
+	t.Setenv("VMWCC_USER", username)
+	t.Setenv("VMWCC_PASS", password)
+}
+
+func runVcc(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	args = append(args, "--cache-dir", "")
+	code = run(context.Background(), &environment{stdout: &out, stderr: &errOut}, args)
+	return out.String(), errOut.String(), code
+}
+
+func TestProducts(t *testing.T) {
+	newFakeServer(t)
+
+	stdout, stderr, code := runVcc(t, "products")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Regexp(t, `(?m)^slug\s+name\s+category\s+latest major version$`, stdout)
+	assert.Regexp(t, `(?m)^vmware_tools\s+VMware Tools\s+`, stdout)
+}
+
+func TestSubProductsJSON(t *testing.T) {
+	newFakeServer(t)
+
+	stdout, stderr, code := runVcc(t, "subproducts", "vmware_tools", "--output", "json")
+	require.Equal(t, exitOK, code, stderr)
+
+	var records []subProductRecord
+	require.Nil(t, json.Unmarshal([]byte(stdout), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "vmtools", records[0].Code)
+	assert.Equal(t, []string{"12_x", "11_x", "10_x"}, records[0].MajorVersions)
+}
+
+func TestVersionsYAML(t *testing.T) {
+	newFakeServer(t)
+
+	stdout, stderr, code := runVcc(t, "versions", "vmware_tools", "vmtools", "--major-version", "11_x", "--output", "yaml")
+	require.Equal(t, exitOK, code, stderr)
+
+	var records []versionRecord
+	require.Nil(t, yaml.Unmarshal([]byte(stdout), &records))
+	require.Len(t, records, 3)
+	assert.Equal(t, versionRecord{Version: "11.3.5", MajorVersion: "11_x", DownloadGroup: "VMTOOLS1135"}, records[0])
+	assert.Equal(t, "11.1.0", records[2].Version)
+}
+
+func TestFilesCSV(t *testing.T) {
+	newFakeServer(t)
+
+	stdout, stderr, code := runVcc(t, "files", "--output=csv", "vmware_tools", "vmtools", "12.*", "--major-version", "12_x")
+	require.Equal(t, exitOK, code, stderr)
+
+	rows, err := csv.NewReader(bytes.NewBufferString(stdout)).ReadAll()
+	require.Nil(t, err)
+	require.Greater(t, len(rows), 1)
+	assert.Equal(t, []string{"file name", "version", "build", "release date", "size", "sha256"}, rows[0])
+	assert.Equal(t, "VMware-Tools-darwin-12.3.0-22234872.tar.gz", rows[1][0])
+	assert.Equal(t, "12.3.0", rows[1][1])
+	assert.Equal(t, "a3902c53a6677444df0612166b9d9ff7f25dccfa40b2da4fd25de5eac6ae9032", rows[1][5])
+}
+
+func TestFilesUnknownMajorVersion(t *testing.T) {
+	newFakeServer(t)
+
+	_, stderr, code := runVcc(t, "files", "vmware_tools", "vmtools", "*", "--major-version", "99_x")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, sdk.ErrorNoMatchingVersions.Error())
+}
+
+func TestFilesLoggedIn(t *testing.T) {
+	srv := newFakeServer(t)
+	setCredentials(t, fakecc.Username, fakecc.Password)
+
+	_, stderr, code := runVcc(t, "files", "vmware_tools", "vmtools", "11.3.5")
+	require.Equal(t, exitOK, code, stderr)
+	assert.NotZero(t, srv.RequestCount("/channel/api/v1.0/dlg/details"))
+}
+
+func TestLoginFailure(t *testing.T) {
+	newFakeServer(t)
+	setCredentials(t, fakecc.Username, "wrong")
+
+	_, stderr, code := runVcc(t, "products")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "vcc products:")
+}
+
+func TestUsage(t *testing.T) {
+	newFakeServer(t)
+
+	for _, args := range [][]string{
+		{"unknown"},
+		{"subproducts"},
+		{"versions", "vmware_tools"},
+		{"products", "--output", "xml"},
+		{"products", "--no-such-flag"},
+	} {
+		_, stderr, code := runVcc(t, args...)
+		assert.Equal(t, exitUsage, code, "%v: %s", args, stderr)
+	}
+
+	_, stderr, code := runVcc(t, "files", "-h")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stderr, "Usage: vcc files [flags] <slug> <subproduct> <version>")
+	assert.Contains(t, stderr, "-dlg-type")
+}
+
+func TestUnknownSubProduct(t *testing.T) {
+	newFakeServer(t)
+
+	_, stderr, code := runVcc(t, "versions", "vmware_tools", "unknown")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "invalid subproduct")
+}
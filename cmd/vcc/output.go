@@ -0,0 +1,66 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// table is the result of a command. JSON and YAML output use records, which hold the
+// same data as rows with field names and types preserved.
+type table struct {
+	headers []string
+	rows    [][]string
+	records interface{}
+}
+
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML, formatCSV:
+		return nil
+	}
+	return usageErrorf("unknown output format %q, must be table, json, yaml or csv", format)
+}
+
+func (t table) write(w io.Writer, format string) (err error) {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(t.records)
+	case formatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(t.records); err != nil {
+			return
+		}
+		return encoder.Close()
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write(t.headers)
+		writer.WriteAll(t.rows)
+		return writer.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
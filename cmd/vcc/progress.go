@@ -0,0 +1,82 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	progressBarWidth    = 30
+	progressRefreshRate = 100 * time.Millisecond
+)
+
+// progressBar draws the progress of a download on a single terminal line
+type progressBar struct {
+	w        io.Writer
+	name     string
+	now      func() time.Time
+	lastDraw time.Time
+	written  int64
+	total    int64
+}
+
+func newProgressBar(w io.Writer, name string) *progressBar {
+	return &progressBar{w: w, name: name, now: time.Now, total: -1}
+}
+
+func (p *progressBar) update(written, total int64) {
+	p.written, p.total = written, total
+	if now := p.now(); now.Sub(p.lastDraw) >= progressRefreshRate {
+		p.lastDraw = now
+		p.draw()
+	}
+}
+
+// done draws the final state and moves to the next line
+func (p *progressBar) done() {
+	p.draw()
+	fmt.Fprintln(p.w)
+}
+
+func (p *progressBar) draw() {
+	if p.total <= 0 {
+		fmt.Fprintf(p.w, "\r%s %s", p.name, formatBytes(p.written))
+		return
+	}
+
+	fraction := float64(p.written) / float64(p.total)
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	fmt.Fprintf(p.w, "\r%s [%s] %3.0f%% %s/%s", p.name, bar, fraction*100, formatBytes(p.written), formatBytes(p.total))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -6,7 +6,9 @@ require (
 	github.com/andybalholm/cascadia v1.3.2
 	github.com/orirawlings/persistent-cookiejar v0.3.2
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.16.0
 	golang.org/x/net v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,5 +19,4 @@ require (
 	golang.org/x/sys v0.15.0 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	gopkg.in/retry.v1 v1.0.3 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -27,23 +28,29 @@ type CurrentUser struct {
 }
 
 const (
-	accountInfoURL = baseURL + "/channel/api/v1.0/ems/accountinfo"
-	currentUserURL = baseURL + "/vmwauth/loggedinuser"
+	accountInfoPath = "/channel/api/v1.0/ems/accountinfo"
+	currentUserPath = "/vmwauth/loggedinuser"
 )
 
 var ErrorNotAuthorized = errors.New("account: you are not authenticated")
 var ErrorNon200Response = errors.New("account: server did not respond with 200 ok")
 
 func (c *Client) AccountInfo() (data AccountInfo, err error) {
+	return c.AccountInfoCtx(context.Background())
+}
+
+func (c *Client) AccountInfoCtx(ctx context.Context) (data AccountInfo, err error) {
+	ctx, span := c.startSpan(ctx, "AccountInfo")
+	defer func() { endSpan(span, err) }()
 	payload := `{"rowLimit": 1000}`
 	var res *http.Response
-	res, err = c.HttpClient.Post(accountInfoURL, "application/json", strings.NewReader(payload))
+	res, err = c.post(ctx, c.endpoints().AccountInfo, "application/json", strings.NewReader(payload))
 	if err != nil {
 		return
 	}
 	defer res.Body.Close()
 
-	if err = c.validateResponseGeneric(res.StatusCode); err != nil {
+	if err = c.validateResponseGeneric(res); err != nil {
 		return
 	}
 
@@ -53,23 +60,33 @@ func (c *Client) AccountInfo() (data AccountInfo, err error) {
 }
 
 func (c *Client) CheckLoggedIn() (err error) {
-	_, err = c.AccountInfo()
+	return c.CheckLoggedInCtx(context.Background())
+}
+
+func (c *Client) CheckLoggedInCtx(ctx context.Context) (err error) {
+	_, err = c.AccountInfoCtx(ctx)
 	return
 }
 
 func (c *Client) CurrentUser() (data CurrentUser, err error) {
-	if err = c.CheckLoggedIn(); err != nil {
+	return c.CurrentUserCtx(context.Background())
+}
+
+func (c *Client) CurrentUserCtx(ctx context.Context) (data CurrentUser, err error) {
+	ctx, span := c.startSpan(ctx, "CurrentUser")
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
 		return
 	}
 
 	var res *http.Response
-	res, err = c.HttpClient.Get(currentUserURL)
+	res, err = c.get(ctx, c.endpoints().CurrentUser)
 	if err != nil {
 		return
 	}
 	defer res.Body.Close()
 
-	if err = c.validateResponseGeneric(res.StatusCode); err != nil {
+	if err = c.validateResponseGeneric(res); err != nil {
 		return
 	}
 
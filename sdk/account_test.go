@@ -21,8 +21,9 @@ func TestAccountInfo(t *testing.T) {
 }
 
 func TestAccountInfoNotLoggedIn(t *testing.T) {
+	client := newFakeClient(t)
 	var accountInfo AccountInfo
-	accountInfo, err = basicClient.AccountInfo()
+	accountInfo, err = client.AccountInfo()
 	assert.ErrorIs(t, err, ErrorNotAuthorized)
 	assert.Empty(t, accountInfo, "Expected response to be empty")
 }
@@ -37,7 +38,8 @@ func TestCurrentUser(t *testing.T) {
 }
 
 func TestCurrentUserNotLoggedIn(t *testing.T) {
-	_, err = basicClient.CurrentUser()
+	client := newFakeClient(t)
+	_, err = client.CurrentUser()
 	assert.ErrorIs(t, err, ErrorNotAuthorized)
 }
 
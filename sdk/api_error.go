@@ -0,0 +1,114 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Only the start of a response body is kept, which is enough for the server's error message
+const maxErrorBodyLen = 512
+
+// Query parameters which are safe to include in errors. Any other value, such as the
+// signature of a download link, is redacted.
+var safeQueryParams = map[string]bool{
+	"category":      true,
+	"product":       true,
+	"version":       true,
+	"dlgType":       true,
+	"downloadGroup": true,
+	"productId":     true,
+	"locale":        true,
+	"isPrivate":     true,
+}
+
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id"}
+
+// APIError describes an unexpected response from Customer Connect. It wraps one of the sentinel
+// errors, e.g. ErrorNotAuthorized, so errors.Is keeps working:
+//
+//	var apiErr *sdk.APIError
+//	if errors.As(err, &apiErr) {
+//		log.Printf("status %d from %s", apiErr.StatusCode, apiErr.URL)
+//	}
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// The start of the response body
+	Body      string
+	RequestID string
+	Err       error
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s %s returned %d", e.Err, e.Method, e.URL, e.StatusCode)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	if e.Body != "" {
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError wraps err with the details of res. The body is read, so it must not be used afterwards.
+func newAPIError(res *http.Response, err error) error {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Err:        err,
+	}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.URL = redactURL(res.Request.URL)
+	}
+	for _, header := range requestIDHeaders {
+		if id := res.Header.Get(header); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+
+	if res.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodyLen+1))
+		apiErr.Body = strings.TrimSpace(string(body))
+		if len(body) > maxErrorBodyLen {
+			apiErr.Body = strings.TrimSpace(string(body[:maxErrorBodyLen])) + "..."
+		}
+	}
+	return apiErr
+}
+
+// Remove credentials and any query values which may be secret from a URL
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+	redacted.User = nil
+	redacted.Fragment = ""
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for key, values := range query {
+			if safeQueryParams[key] {
+				continue
+			}
+			for i := range values {
+				values[i] = "REDACTED"
+			}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
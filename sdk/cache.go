@@ -0,0 +1,171 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const DefaultCacheTTL = time.Hour
+
+// Cache stores responses of the public catalog endpoints, keyed by the full request URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (value []byte, ok bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+type cacheEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// MemoryCache keeps responses for the lifetime of the process
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryCache) Get(key string) (value []byte, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	if m.now().After(entry.Expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = cacheEntry{Value: value, Expires: m.now().Add(ttl)}
+}
+
+// FileCache persists responses in a directory, so they can be reused across runs
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+func NewFileCache(dir string) (cache *FileCache, err error) {
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	cache = &FileCache{dir: dir, now: time.Now}
+	return
+}
+
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *FileCache) Get(key string) (value []byte, ok bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return
+	}
+
+	var entry cacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil || f.now().After(entry.Expires) {
+		os.Remove(f.path(key))
+		return
+	}
+	return entry.Value, true
+}
+
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := json.Marshal(cacheEntry{Value: value, Expires: f.now().Add(ttl)})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(f.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err = os.Rename(tmp.Name(), f.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+type cacheRefreshKey struct{}
+
+// Requests made with the returned context skip cache lookups, but still store their responses
+func withCacheRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheRefreshKey{}, true)
+}
+
+// getCached serves public catalog requests from the cache when one is configured.
+// Only successful responses are stored.
+func (c *Client) getCached(ctx context.Context, endpoint string) (res *http.Response, err error) {
+	if c.cache == nil {
+		return c.get(ctx, endpoint)
+	}
+
+	refresh, _ := ctx.Value(cacheRefreshKey{}).(bool)
+	if body, ok := c.cache.Get(endpoint); ok && !refresh {
+		res = &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+		return
+	}
+
+	res, err = c.get(ctx, endpoint)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return
+	}
+
+	var body []byte
+	body, err = io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.cacheTTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	c.cache.Set(endpoint, body, ttl)
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return
+}
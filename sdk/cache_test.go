@@ -0,0 +1,92 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dlgHeaderTestPath = "/channel/public/api/v1.0/products/getDLGHeader"
+
+func TestMemoryCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("key", []byte("value"), time.Minute)
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok)
+}
+
+func TestFileCacheTTL(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	require.Nil(t, err)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+
+	cache.Set("key", []byte("value"), time.Minute)
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok)
+	assert.NoFileExists(t, cache.path("key"))
+}
+
+func TestClientMemoryCache(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.Cache = NewMemoryCache()
+	client := NewClient(opts)
+
+	first, err := client.GetVersionMap("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	requests := srv.RequestCount(dlgHeaderTestPath)
+	assert.Greater(t, requests, 0)
+
+	second, err := client.GetVersionMap("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, requests, srv.RequestCount(dlgHeaderTestPath))
+
+	// Errors are not cached
+	_, err = client.GetDlgHeader("VMTOOLS666", "1073")
+	assert.ErrorIs(t, err, ErrorDlgHeader)
+	_, err = client.GetDlgHeader("VMTOOLS666", "1073")
+	assert.ErrorIs(t, err, ErrorDlgHeader)
+	assert.Equal(t, requests+2, srv.RequestCount(dlgHeaderTestPath))
+}
+
+func TestClientFileCacheAcrossClients(t *testing.T) {
+	srv := newFakeServer(t)
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		cache, err := NewFileCache(dir)
+		require.Nil(t, err)
+		opts := fakeClientOptions(srv)
+		opts.Cache = cache
+		opts.CacheTTL = time.Minute
+
+		var dlgDetails DlgDetails
+		dlgDetails, err = NewClient(opts).GetDlgDetails("VMTOOLS1111", "1073")
+		require.Nil(t, err)
+		assert.NotEmpty(t, dlgDetails.DownloadDetails)
+	}
+	assert.Equal(t, 1, srv.RequestCount("/channel/public/api/v1.0/dlg/details"))
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const CatalogSnapshotVersion = 1
+
+var (
+	ErrorOffline                = errors.New("snapshot: not available without network access")
+	ErrorNotInSnapshot          = errors.New("snapshot: not included in the catalog snapshot")
+	ErrorCatalogSnapshotVersion = errors.New("snapshot: unsupported snapshot version")
+)
+
+// CatalogFilter selects the part of the catalog to export
+type CatalogFilter struct {
+	// Slugs to export. Empty exports every product, which takes thousands of requests.
+	Slugs []string
+	// SubProducts to export. Empty exports every sub-product.
+	SubProducts []string
+	// Defaults to PRODUCT_BINARY
+	DlgTypes []string
+	// Versions is a VersionConstraint limiting the versions exported. Empty exports every version.
+	Versions string
+}
+
+// CatalogSnapshot holds the catalog as seen by a client, so it can be queried without network
+// access by setting ClientOptions.Snapshot. Keys follow the catalog hierarchy, joined with slashes.
+type CatalogSnapshot struct {
+	SnapshotVersion int       `json:"snapshotVersion"`
+	CreatedAt       time.Time `json:"createdAt"`
+	// Keyed by slug
+	Products map[string]ProductDetails `json:"products"`
+	// Sub-product maps keyed by <slug>/<dlgType>
+	SubProducts map[string]map[string]SubProductDetails `json:"subProducts"`
+	// Version maps keyed by <slug>/<dlgType>/<subProduct>
+	Versions map[string]map[string]APIVersions `json:"versions"`
+	// Keyed by <downloadGroup>/<productId>. Only the files are kept, entitlement and EULA state
+	// are specific to an account.
+	DlgDetails map[string]DlgDetails `json:"dlgDetails"`
+}
+
+func snapshotKey(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// ReadCatalogSnapshot decodes a snapshot written by ExportCatalog
+func ReadCatalogSnapshot(r io.Reader) (snapshot *CatalogSnapshot, err error) {
+	if err = json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.SnapshotVersion != CatalogSnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrorCatalogSnapshotVersion, snapshot.SnapshotVersion)
+	}
+	return
+}
+
+// ExportCatalog writes a snapshot of the part of the catalog selected by filter to w as JSON
+func (c *Client) ExportCatalog(w io.Writer, filter CatalogFilter) (err error) {
+	return c.ExportCatalogCtx(context.Background(), w, filter)
+}
+
+func (c *Client) ExportCatalogCtx(ctx context.Context, w io.Writer, filter CatalogFilter) (err error) {
+	ctx, span := c.startSpan(ctx, "ExportCatalog")
+	defer func() { endSpan(span, err) }()
+
+	var snapshot *CatalogSnapshot
+	if snapshot, err = c.snapshotCatalog(ctx, filter); err != nil {
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+func (c *Client) snapshotCatalog(ctx context.Context, filter CatalogFilter) (snapshot *CatalogSnapshot, err error) {
+	versions := filter.Versions
+	if versions == "" {
+		versions = "*"
+	}
+	var constraint VersionConstraint
+	if constraint, err = ParseVersionConstraint(versions); err != nil {
+		return
+	}
+
+	dlgTypes := filter.DlgTypes
+	if len(dlgTypes) == 0 {
+		dlgTypes = []string{DefaultDlgType}
+	}
+
+	snapshot = &CatalogSnapshot{
+		SnapshotVersion: CatalogSnapshotVersion,
+		CreatedAt:       time.Now().UTC(),
+		SubProducts:     make(map[string]map[string]SubProductDetails),
+		Versions:        make(map[string]map[string]APIVersions),
+		DlgDetails:      make(map[string]DlgDetails),
+	}
+	if snapshot.Products, err = c.GetProductsMapCtx(ctx); err != nil {
+		return
+	}
+
+	slugs := filter.Slugs
+	if len(slugs) == 0 {
+		for slug := range snapshot.Products {
+			slugs = append(slugs, slug)
+		}
+		sort.Strings(slugs)
+	}
+
+	for _, slug := range slugs {
+		if _, ok := snapshot.Products[slug]; !ok {
+			err = fmt.Errorf("%w: %s", ErrorInvalidSlug, slug)
+			return
+		}
+
+		for _, dlgType := range dlgTypes {
+			var subProductMap map[string]SubProductDetails
+			if subProductMap, err = c.GetSubProductsMapCtx(ctx, slug, dlgType, ""); err != nil {
+				return
+			}
+
+			exported := make(map[string]SubProductDetails)
+			for code, subProduct := range subProductMap {
+				if len(filter.SubProducts) > 0 && !slices.Contains(filter.SubProducts, code) {
+					continue
+				}
+				exported[code] = subProduct
+
+				var versionMap map[string]APIVersions
+				if versionMap, err = c.GetVersionMapCtx(ctx, slug, code, dlgType); err != nil {
+					return
+				}
+
+				exportedVersions := make(map[string]APIVersions)
+				for _, apiVersions := range matchVersionConstraint(constraint, versionMap) {
+					exportedVersions[apiVersions.MinorVersion] = versionMap[apiVersions.MinorVersion]
+
+					productID := subProduct.DlgListByVersion[apiVersions.MajorVersion].ProductID
+					key := snapshotKey(apiVersions.Code, productID)
+					if _, ok := snapshot.DlgDetails[key]; ok {
+						continue
+					}
+					var dlgDetails DlgDetails
+					if dlgDetails, err = c.GetDlgDetailsCtx(ctx, apiVersions.Code, productID); err != nil {
+						return
+					}
+					snapshot.DlgDetails[key] = DlgDetails{DownloadDetails: dlgDetails.DownloadDetails}
+				}
+				snapshot.Versions[snapshotKey(slug, dlgType, code)] = exportedVersions
+			}
+			snapshot.SubProducts[snapshotKey(slug, dlgType)] = exported
+		}
+	}
+	return
+}
+
+// offline reports whether download details are answered from the snapshot as well.
+// Only a logged in client can see entitlement and EULA state, so it always asks the service.
+func (c *Client) offline() bool {
+	return c.snapshot != nil && c.jar == nil
+}
+
+// offlineTransport fails every request, so a snapshot client never touches the network
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%w: %s %s", ErrorOffline, req.Method, redactURL(req.URL))
+}
+
+func (s *CatalogSnapshot) subProducts(slug, dlgType, majorVersion string) (subProductMap map[string]SubProductDetails, err error) {
+	exported, ok := s.SubProducts[snapshotKey(slug, dlgType)]
+	if !ok {
+		err = fmt.Errorf("%w: %s %s", ErrorNotInSnapshot, slug, dlgType)
+		return
+	}
+
+	subProductMap = make(map[string]SubProductDetails, len(exported))
+	for code, subProduct := range exported {
+		if majorVersion == "" {
+			subProductMap[code] = subProduct
+			continue
+		}
+		// As when querying a single major version, sub-products only hold that version
+		if dlgList, ok := subProduct.DlgListByVersion[majorVersion]; ok {
+			subProduct.DlgListByVersion = map[string]DlgList{majorVersion: dlgList}
+			subProductMap[code] = subProduct
+		}
+	}
+	if majorVersion != "" && len(subProductMap) == 0 {
+		err = ErrorInvalidSubProductMajorVersion
+	}
+	return
+}
+
+func (s *CatalogSnapshot) versions(slug, subProduct, dlgType string) (versionMap map[string]APIVersions, err error) {
+	exported, ok := s.Versions[snapshotKey(slug, dlgType, subProduct)]
+	if !ok {
+		err = fmt.Errorf("%w: %s %s %s", ErrorNotInSnapshot, slug, subProduct, dlgType)
+		return
+	}
+
+	versionMap = make(map[string]APIVersions, len(exported))
+	for version, apiVersions := range exported {
+		versionMap[version] = apiVersions
+	}
+	return
+}
+
+func (s *CatalogSnapshot) dlgDetails(downloadGroup, productID string) (dlgDetails DlgDetails, err error) {
+	dlgDetails, ok := s.DlgDetails[snapshotKey(downloadGroup, productID)]
+	if !ok {
+		err = fmt.Errorf("%w: %s %s", ErrorNotInSnapshot, downloadGroup, productID)
+	}
+	return
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+var testCatalogFilter = CatalogFilter{
+	Slugs:       []string{"vmware_tools"},
+	SubProducts: []string{"vmtools"},
+	Versions:    "11.*",
+}
+
+func exportTestSnapshot(t *testing.T) *CatalogSnapshot {
+	t.Helper()
+
+	srv := newFakeServer(t)
+	var buf bytes.Buffer
+	require.Nil(t, NewClient(fakeClientOptions(srv)).ExportCatalog(&buf, testCatalogFilter))
+	srv.Close()
+
+	snapshot, err := ReadCatalogSnapshot(&buf)
+	require.Nil(t, err)
+	return snapshot
+}
+
+func TestCatalogSnapshot(t *testing.T) {
+	snapshot := exportTestSnapshot(t)
+	assert.Contains(t, snapshot.Versions, "vmware_tools/PRODUCT_BINARY/vmtools")
+	for version := range snapshot.Versions["vmware_tools/PRODUCT_BINARY/vmtools"] {
+		assert.True(t, strings.HasPrefix(version, "11."), version)
+	}
+
+	client := NewClient(ClientOptions{Snapshot: snapshot})
+
+	versions, err := client.GetVersionSlice("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Contains(t, versions, "11.3.5")
+	assert.NotContains(t, versions, "12.3.0")
+
+	apiVersions, err := client.FindVersion("vmware_tools", "vmtools", "11.3.*", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Equal(t, "11.3.5", apiVersions.MinorVersion)
+
+	files, err := client.GetFileArray("vmware_tools", "vmtools", "11.3.5", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Contains(t, files, "VMware-Tools-darwin-11.3.5-18557794.tar.gz")
+
+	found, err := client.FindDlgDetails(apiVersions.Code, "1073", "VMware-Tools-darwin-*")
+	require.Nil(t, err)
+	require.Len(t, found.DownloadDetails, 1)
+	assert.Equal(t, "VMware-Tools-darwin-11.3.5-18557794.tar.gz", found.DownloadDetails[0].FileName)
+
+	_, err = client.FetchDownloadLink(DownloadPayload{DownloadGroup: apiVersions.Code, ProductId: "1073"})
+	assert.ErrorIs(t, err, ErrorOffline)
+}
+
+func TestCatalogSnapshotLoggedIn(t *testing.T) {
+	snapshot := exportTestSnapshot(t)
+	srv := newFakeServer(t)
+
+	opts := fakeClientOptions(srv)
+	opts.Snapshot = snapshot
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.3.5", "VMware-Tools-darwin-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.Len(t, payloads, 1)
+
+	_, err = client.FetchDownloadLink(payloads[0])
+	assert.Nil(t, err)
+	// The catalog still comes from the snapshot
+	assert.Equal(t, 0, srv.RequestCount("/channel/public/api/v1.0/products/getRelatedDLGList"))
+}
+
+func TestCatalogSnapshotMissingEntries(t *testing.T) {
+	client := NewClient(ClientOptions{Snapshot: exportTestSnapshot(t)})
+
+	_, err := client.GetVersionSlice("vmware_tools", "vmtools", "DRIVERS_TOOLS")
+	assert.ErrorIs(t, err, ErrorNotInSnapshot)
+
+	_, err = client.GetVersionSlice("vmware_vsphere", "esxi", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, ErrorNotInSnapshot)
+
+	_, err = client.GetVersionSlice("not_a_product", "esxi", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, ErrorInvalidSlug)
+
+	_, err = client.GetFileArray("vmware_tools", "vmtools", "12.3.0", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, ErrorInvalidVersion)
+}
+
+func TestReadCatalogSnapshotVersion(t *testing.T) {
+	_, err := ReadCatalogSnapshot(strings.NewReader(`{"snapshotVersion": 2}`))
+	require.ErrorIs(t, err, ErrorCatalogSnapshotVersion)
+	assert.Contains(t, err.Error(), "2")
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientOptions configures a Client created by NewClient or LoginWithOptions.
+type ClientOptions struct {
+	Endpoints Endpoints
+
+	// Transport sends the requests of Login, CheckConnectivity and every later call.
+	// Use NewTransport to configure a proxy, CA bundle or client certificate.
+	// Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Cache stores responses of the public catalog endpoints, which avoids repeating the
+	// dozens of requests needed to resolve a download. Use NewMemoryCache or NewFileCache.
+	Cache Cache
+	// How long cached responses are valid for. Defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	// Credentials are used to log in again when the session expires during a run.
+	// Defaults to the username and password passed to Login.
+	Credentials CredentialProvider
+	// OnSessionRefresh is called after the client logged in again
+	OnSessionRefresh func(jar http.CookieJar)
+	// SessionStore persists the session across runs, see MemorySessionStore and FileSessionStore.
+	// It is saved after every login, including when the session is refreshed.
+	SessionStore SessionStore
+
+	// RetryPolicy for requests failing with a transient error. Defaults to DefaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// RateLimiter limits the rate of requests, including retries and requests made to log in
+	RateLimiter *RateLimiter
+
+	// Logger receives debug events for HTTP requests, login stages and catalog normalization
+	Logger *slog.Logger
+	// Tracer receives spans for public methods and HTTP requests
+	Tracer Tracer
+
+	// Snapshot answers catalog queries instead of the live service, see ExportCatalog.
+	// A client created by NewClient then makes no network requests at all. Logged in clients
+	// still fetch download details live, as entitlement and EULA state are not in the snapshot.
+	Snapshot *CatalogSnapshot
+}
+
+// NewClient returns an unauthenticated client, which can be used to query the public endpoints.
+// Use Login or LoginWithOptions to get a client which can fetch download links.
+func NewClient(opts ClientOptions) *Client {
+	if opts.Snapshot != nil {
+		return newClient(&http.Client{Transport: offlineTransport{}}, opts)
+	}
+	return newClient(newHTTPClient(nil, opts), opts)
+}
+
+// newHTTPClient builds the client used for all requests, with the transports configured by opts
+func newHTTPClient(jar http.CookieJar, opts ClientOptions) *http.Client {
+	policy := DefaultRetryPolicy()
+	if opts.RetryPolicy != nil {
+		policy = *opts.RetryPolicy
+	}
+
+	logger := loggerOrDiscard(opts.Logger)
+
+	base := opts.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var transport http.RoundTripper = &loggingTransport{next: base, logger: logger}
+	if opts.Tracer != nil {
+		transport = &tracingTransport{next: transport, tracer: opts.Tracer}
+	}
+	if opts.RateLimiter != nil {
+		transport = &rateLimitTransport{next: transport, limiter: opts.RateLimiter}
+	}
+
+	return &http.Client{
+		Jar:       jar,
+		Transport: newRetryTransport(transport, policy, logger),
+	}
+}
+
+func newClient(httpClient *http.Client, opts ClientOptions) *Client {
+	return &Client{
+		HttpClient: httpClient,
+		Endpoints:  opts.Endpoints.withDefaults(),
+		cache:      opts.Cache,
+		cacheTTL:   opts.CacheTTL,
+		logger:     opts.Logger,
+		tracer:     opts.Tracer,
+		snapshot:   opts.Snapshot,
+
+		credentials:      opts.Credentials,
+		onSessionRefresh: opts.OnSessionRefresh,
+		sessionStore:     opts.SessionStore,
+	}
+}
+
+// Clients created without NewClient fall back to the default endpoints
+func (c *Client) endpoints() Endpoints {
+	return c.Endpoints.withDefaults()
+}
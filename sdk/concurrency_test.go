@@ -0,0 +1,69 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -race to detect unsynchronised access to shared client state
+func TestConcurrentClient(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.Cache = NewMemoryCache()
+	client := fakeLogin(t, srv)
+	cachedClient := NewClient(opts)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.3.5", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.NotEmpty(t, payloads)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			versions, err := client.GetVersionMap("vmware_tools", "vmtools", "PRODUCT_BINARY")
+			assert.Nil(t, err)
+			assert.Contains(t, versions, "11.3.5")
+		}()
+		go func(payload DownloadPayload) {
+			defer wg.Done()
+			authorizedDownload, err := client.FetchDownloadLink(payload)
+			assert.Nil(t, err)
+			assert.NotEmpty(t, authorizedDownload.DownloadURL)
+		}(payloads[i%len(payloads)])
+		go func() {
+			defer wg.Done()
+			versions, err := cachedClient.GetVersionSlice("vmware_vsphere", "esxi", "PRODUCT_BINARY")
+			assert.Nil(t, err)
+			assert.Equal(t, "8.0U2", versions[0])
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentRefreshCatalog(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, client.RefreshCatalog())
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.GetMajorVersionsSlice("vmware_tools")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+}
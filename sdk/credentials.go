@@ -0,0 +1,190 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CredentialProvider supplies the username and password used to log in. It is called again
+// whenever the session expires, so implementations can return rotated credentials.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+var ErrorMissingCredentials = errors.New("credentials: no credentials found")
+
+const (
+	DefaultUsernameEnv = "VMWCC_USER"
+	DefaultPasswordEnv = "VMWCC_PASS"
+)
+
+// StaticCredentials always returns the same username and password
+type StaticCredentials struct {
+	Username string
+	Password string
+}
+
+func (s StaticCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	return s.Username, s.Password, nil
+}
+
+// EnvCredentials reads the credentials from environment variables, VMWCC_USER and VMWCC_PASS by default
+type EnvCredentials struct {
+	UsernameVar string
+	PasswordVar string
+}
+
+func (e EnvCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	usernameVar, passwordVar := e.UsernameVar, e.PasswordVar
+	if usernameVar == "" {
+		usernameVar = DefaultUsernameEnv
+	}
+	if passwordVar == "" {
+		passwordVar = DefaultPasswordEnv
+	}
+
+	username, password = os.Getenv(usernameVar), os.Getenv(passwordVar)
+	if username == "" || password == "" {
+		err = fmt.Errorf("%w: %s and %s must be set", ErrorMissingCredentials, usernameVar, passwordVar)
+	}
+	return
+}
+
+// NetrcCredentials reads the credentials from a netrc file, ~/.netrc by default.
+// The entry for Machine is used, falling back to the default entry.
+type NetrcCredentials struct {
+	Path string
+	// Defaults to the host of DefaultBaseURL
+	Machine string
+}
+
+func (n NetrcCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	path := n.Path
+	if path == "" {
+		var home string
+		if home, err = os.UserHomeDir(); err != nil {
+			return
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	machine := n.Machine
+	if machine == "" {
+		machine = defaultHost()
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return
+	}
+
+	entries, defaultEntry := parseNetrc(string(data))
+	entry, ok := entries[machine]
+	if !ok {
+		entry = defaultEntry
+	}
+	if entry != nil {
+		username, password = entry.login, entry.password
+	}
+
+	if username == "" || password == "" {
+		err = fmt.Errorf("%w: no entry for %s in %s", ErrorMissingCredentials, machine, path)
+	}
+	return
+}
+
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// Parse the entries of a netrc file keyed by machine, along with the default entry
+func parseNetrc(data string) (entries map[string]*netrcEntry, defaultEntry *netrcEntry) {
+	entries = make(map[string]*netrcEntry)
+
+	var current *netrcEntry
+	tokens := strings.Fields(data)
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			current = nil
+			if i+1 < len(tokens) {
+				i++
+				current = &netrcEntry{}
+				entries[tokens[i]] = current
+			}
+		case "default":
+			current = &netrcEntry{}
+			defaultEntry = current
+		case "login", "password":
+			if current == nil || i+1 >= len(tokens) {
+				continue
+			}
+			i++
+			if tokens[i-1] == "login" {
+				current.login = tokens[i]
+			} else {
+				current.password = tokens[i]
+			}
+		case "macdef":
+			// Macros are not supported
+			current = nil
+		}
+	}
+	return
+}
+
+// ExecCredentials runs a command which follows the git credential helper protocol: the request
+// is written to stdin as key=value lines, e.g. host=customerconnect.vmware.com, and the
+// command prints username=... and password=... lines.
+type ExecCredentials struct {
+	Command []string
+}
+
+func (e ExecCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	if len(e.Command) == 0 {
+		err = fmt.Errorf("%w: no command configured", ErrorMissingCredentials)
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...)
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", defaultHost()))
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		err = fmt.Errorf("credentials: %s: %w", e.Command[0], err)
+		return
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch key {
+		case "username":
+			username = value
+		case "password":
+			password = value
+		}
+	}
+
+	if username == "" || password == "" {
+		err = fmt.Errorf("%w: %s did not return a username and password", ErrorMissingCredentials, e.Command[0])
+	}
+	return
+}
+
+func defaultHost() string {
+	u, _ := url.Parse(DefaultBaseURL)
+	return u.Hostname()
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orirawlings/persistent-cookiejar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TEST_VCC_USER", "user@example.com")
+	t.Setenv("TEST_VCC_PASS", "secret")
+
+	username, password, err := EnvCredentials{UsernameVar: "TEST_VCC_USER", PasswordVar: "TEST_VCC_PASS"}.Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "user@example.com", username)
+	assert.Equal(t, "secret", password)
+
+	_, _, err = EnvCredentials{UsernameVar: "TEST_VCC_USER", PasswordVar: "TEST_VCC_MISSING"}.Credentials(context.Background())
+	assert.ErrorIs(t, err, ErrorMissingCredentials)
+}
+
+func TestNetrcCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	netrc := `machine example.com login other password other-secret
+machine customerconnect.vmware.com
+	login user@example.com
+	password secret
+default login fallback password fallback-secret
+`
+	require.Nil(t, os.WriteFile(path, []byte(netrc), 0600))
+
+	username, password, err := NetrcCredentials{Path: path}.Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "user@example.com", username)
+	assert.Equal(t, "secret", password)
+
+	username, password, err = NetrcCredentials{Path: path, Machine: "unknown.example.com"}.Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "fallback", username)
+	assert.Equal(t, "fallback-secret", password)
+
+	require.Nil(t, os.WriteFile(path, []byte("machine example.com login other password other-secret\n"), 0600))
+	_, _, err = NetrcCredentials{Path: path}.Credentials(context.Background())
+	assert.ErrorIs(t, err, ErrorMissingCredentials)
+}
+
+func TestExecCredentials(t *testing.T) {
+	username, password, err := ExecCredentials{Command: []string{helperCommand(t, "credentials")}}.Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "user@example.com", username)
+	assert.Equal(t, "secret", password)
+
+	_, _, err = ExecCredentials{Command: []string{helperCommand(t, "username-only")}}.Credentials(context.Background())
+	assert.ErrorIs(t, err, ErrorMissingCredentials)
+
+	_, _, err = ExecCredentials{Command: []string{helperCommand(t, "fail")}}.Credentials(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestLoginWithProvider(t *testing.T) {
+	srv := newFakeServer(t)
+	keyring := &FileKeyring{Path: filepath.Join(t.TempDir(), "keyring.json")}
+	require.Nil(t, keyring.Set(DefaultKeyringService, fakecc.Username, fakecc.Password))
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	provider := KeyringCredentials{Keyring: keyring, Username: fakecc.Username}
+	client, err := LoginWithProvider(context.Background(), provider, jar, fakeClientOptions(srv))
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+
+	jar, _ = cookiejar.New(&cookiejar.Options{NoPersist: true})
+	provider = KeyringCredentials{Keyring: keyring, Username: "unknown@example.com"}
+	_, err = LoginWithProvider(context.Background(), provider, jar, fakeClientOptions(srv))
+	assert.ErrorIs(t, err, ErrorMissingCredentials)
+}
@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -52,8 +53,8 @@ type FoundDownload struct {
 }
 
 const (
-	dlgDetailsURLAuthenticated = baseURL + "/channel/api/v1.0/dlg/details"
-	dlgDetailsURLPublic        = baseURL + "/channel/public/api/v1.0/dlg/details"
+	dlgDetailsPathAuthenticated = "/channel/api/v1.0/dlg/details"
+	dlgDetailsPathPublic        = "/channel/public/api/v1.0/dlg/details"
 )
 
 var (
@@ -66,29 +67,39 @@ var (
 
 // curl "https://my.vmware.com/channel/public/api/v1.0/dlg/details?downloadGroup=VMTOOLS1130&productId=1073" |jq
 func (c *Client) GetDlgDetails(downloadGroup, productId string) (data DlgDetails, err error) {
-	err = c.CheckLoggedIn()
+	return c.GetDlgDetailsCtx(context.Background(), downloadGroup, productId)
+}
+
+func (c *Client) GetDlgDetailsCtx(ctx context.Context, downloadGroup, productId string) (data DlgDetails, err error) {
+	ctx, span := c.startSpan(ctx, "GetDlgDetails", attr(AttrDownloadGroup, downloadGroup), attr(AttrProductID, productId))
+	defer func() { endSpan(span, err) }()
+	if c.offline() {
+		return c.snapshot.dlgDetails(downloadGroup, productId)
+	}
+	err = c.CheckLoggedInCtx(ctx)
 	// Use public URL when user is not logged in
 	// This will not return entitlement or EULA sections
-	var dlgDetailsURL string
-	if err != nil {
-		dlgDetailsURL = dlgDetailsURLPublic
-	} else {
-		dlgDetailsURL = dlgDetailsURLAuthenticated
-	}
-
+	// Only public responses are cached, as entitlement and EULA state can change
 	search_string := fmt.Sprintf("?downloadGroup=%s&productId=%s", downloadGroup, productId)
 	var res *http.Response
-	res, err = c.HttpClient.Get(dlgDetailsURL + search_string)
+	if ctx.Err() != nil {
+		err = ctx.Err()
+		return
+	} else if err != nil {
+		res, err = c.getCached(ctx, c.endpoints().DlgDetailsPublic+search_string)
+	} else {
+		res, err = c.get(ctx, c.endpoints().DlgDetailsAuthenticated+search_string)
+	}
 	if err != nil {
 		return
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode == 400 {
-		err = ErrorDlgDetailsInputs
+		err = newAPIError(res, ErrorDlgDetailsInputs)
 		return
 	} else if res.StatusCode == 401 {
-		err = ErrorNotAuthenticated
+		err = newAPIError(res, ErrorNotAuthenticated)
 		return
 	}
 
@@ -98,12 +109,20 @@ func (c *Client) GetDlgDetails(downloadGroup, productId string) (data DlgDetails
 }
 
 func (c *Client) FindDlgDetails(downloadGroup, productId, fileName string) (data FoundDownload, err error) {
-	if err = c.CheckLoggedIn(); err != nil {
-		return
+	return c.FindDlgDetailsCtx(context.Background(), downloadGroup, productId, fileName)
+}
+
+func (c *Client) FindDlgDetailsCtx(ctx context.Context, downloadGroup, productId, fileName string) (data FoundDownload, err error) {
+	ctx, span := c.startSpan(ctx, "FindDlgDetails", attr(AttrDownloadGroup, downloadGroup), attr(AttrProductID, productId), attr(AttrFileName, fileName))
+	defer func() { endSpan(span, err) }()
+	if !c.offline() {
+		if err = c.CheckLoggedInCtx(ctx); err != nil {
+			return
+		}
 	}
 
 	var dlgDetails DlgDetails
-	dlgDetails, err = c.GetDlgDetails(downloadGroup, productId)
+	dlgDetails, err = c.GetDlgDetailsCtx(ctx, downloadGroup, productId)
 	if err != nil {
 		return
 	}
@@ -129,15 +148,21 @@ func (c *Client) FindDlgDetails(downloadGroup, productId, fileName string) (data
 }
 
 func (c *Client) GetFileArray(slug, subProduct, version, dlgType string) (data []string, err error) {
+	return c.GetFileArrayCtx(context.Background(), slug, subProduct, version, dlgType)
+}
+
+func (c *Client) GetFileArrayCtx(ctx context.Context, slug, subProduct, version, dlgType string) (data []string, err error) {
+	ctx, span := c.startSpan(ctx, "GetFileArray", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrVersion, version), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var productID string
 	var apiVersions APIVersions
-	productID, apiVersions, err = c.GetDlgProduct(slug, subProduct, version, dlgType)
+	productID, apiVersions, err = c.GetDlgProductCtx(ctx, slug, subProduct, version, dlgType)
 	if err != nil {
 		return
 	}
 
 	var dlgDetails DlgDetails
-	dlgDetails, err = c.GetDlgDetails(apiVersions.Code, productID)
+	dlgDetails, err = c.GetDlgDetailsCtx(ctx, apiVersions.Code, productID)
 	if err != nil {
 		return
 	}
@@ -152,14 +177,20 @@ func (c *Client) GetFileArray(slug, subProduct, version, dlgType string) (data [
 }
 
 func (c *Client) GetDlgProduct(slug, subProduct, version, dlgType string) (productID string, apiVersions APIVersions, err error) {
+	return c.GetDlgProductCtx(context.Background(), slug, subProduct, version, dlgType)
+}
+
+func (c *Client) GetDlgProductCtx(ctx context.Context, slug, subProduct, version, dlgType string) (productID string, apiVersions APIVersions, err error) {
+	ctx, span := c.startSpan(ctx, "GetDlgProduct", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrVersion, version), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	// Find the API version details
-	apiVersions, err = c.FindVersion(slug, subProduct, version, dlgType)
+	apiVersions, err = c.FindVersionCtx(ctx, slug, subProduct, version, dlgType)
 	if err != nil {
 		return
 	}
 
 	var subProductDetails DlgList
-	subProductDetails, err = c.GetSubProductDetails(slug, subProduct, apiVersions.MajorVersion, dlgType)
+	subProductDetails, err = c.GetSubProductDetailsCtx(ctx, slug, subProduct, apiVersions.MajorVersion, dlgType)
 	if err != nil {
 		return
 	}
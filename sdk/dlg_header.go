@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -47,21 +48,27 @@ type Dlg struct {
 }
 
 const (
-	dlgHeaderURL = baseURL + "/channel/public/api/v1.0/products/getDLGHeader"
+	dlgHeaderPath = "/channel/public/api/v1.0/products/getDLGHeader"
 )
 
 var ErrorDlgHeader = errors.New("dlgHeader: downloadGroup or productId invalid")
 
 // curl "https://my.vmware.com/channel/public/api/v1.0/products/getDLGHeader?downloadGroup=VMTOOLS1130&productId=1073" |jq
 func (c *Client) GetDlgHeader(downloadGroup, productId string) (data DlgHeader, err error) {
+	return c.GetDlgHeaderCtx(context.Background(), downloadGroup, productId)
+}
+
+func (c *Client) GetDlgHeaderCtx(ctx context.Context, downloadGroup, productId string) (data DlgHeader, err error) {
+	ctx, span := c.startSpan(ctx, "GetDlgHeader", attr(AttrDownloadGroup, downloadGroup), attr(AttrProductID, productId))
+	defer func() { endSpan(span, err) }()
 	search_string := fmt.Sprintf("?downloadGroup=%s&productId=%s", downloadGroup, productId)
 	var res *http.Response
-	res, err = c.HttpClient.Get(dlgHeaderURL + search_string)
+	res, err = c.getCached(ctx, c.endpoints().DlgHeader+search_string)
 	if err != nil {return}
 	defer res.Body.Close()
 
 	if res.StatusCode == 400 {
-		err = ErrorDlgHeader
+		err = newAPIError(res, ErrorDlgHeader)
 		return
 	}
 
@@ -10,22 +10,25 @@ import (
 )
 
 func TestGetHeaderSuccess(t *testing.T) {
+	client := newFakeClient(t)
 	var dlgHeader DlgHeader
-	dlgHeader, err = basicClient.GetDlgHeader("VMTOOLS1130", "1073")
+	dlgHeader, err = client.GetDlgHeader("VMTOOLS1135", "1073")
 	assert.Nil(t, err)
 	assert.Equal(t, dlgHeader.Product.Productmap, "vmware_tools", "Expected product name vmware_tools")
 }
 
 func TestGetHeaderInvalidProductId(t *testing.T) {
+	client := newFakeClient(t)
 	var dlgHeader DlgHeader
-	dlgHeader, err = basicClient.GetDlgHeader("VMTOOLS1130", "666666")
+	dlgHeader, err = client.GetDlgHeader("VMTOOLS1135", "666666")
 	assert.ErrorIs(t, err, ErrorDlgHeader)
 	assert.Empty(t, dlgHeader, "Expected response to be empty")
 }
 
 func TestGetHeaderInvalidDownloadGroup(t *testing.T) {
+	client := newFakeClient(t)
 	var dlgHeader DlgHeader
-	dlgHeader, err = basicClient.GetDlgHeader("VMTOOLS666", "1073")
+	dlgHeader, err = client.GetDlgHeader("VMTOOLS666", "1073")
 	assert.ErrorIs(t, err, ErrorDlgHeader)
 	assert.Empty(t, dlgHeader, "Expected response to be empty")
 }
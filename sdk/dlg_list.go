@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -27,22 +28,28 @@ type DlgEditionsLists struct {
 }
 
 const (
-	dlgListURL = baseURL + "/channel/public/api/v1.0/products/getRelatedDLGList"
+	dlgListPath = "/channel/public/api/v1.0/products/getRelatedDLGList"
 )
 
 // curl "https://my.vmware.com/channel/public/api/v1.0/products/getRelatedDLGList?category= &product=vmware_vsan&version=7_0&dlgType=PRODUCT_BINARY" |jq
 func (c *Client) GetDlgEditionsList(slug, majorVersion, dlgType string) (data []DlgEditionsLists, err error) {
+	return c.GetDlgEditionsListCtx(context.Background(), slug, majorVersion, dlgType)
+}
+
+func (c *Client) GetDlgEditionsListCtx(ctx context.Context, slug, majorVersion, dlgType string) (data []DlgEditionsLists, err error) {
+	ctx, span := c.startSpan(ctx, "GetDlgEditionsList", attr(AttrSlug, slug), attr(AttrMajorVersion, majorVersion), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var category string
-	category, err = c.GetCategory(slug)
+	category, err = c.GetCategoryCtx(ctx, slug)
 	if err != nil {return}
 
 	search_string := fmt.Sprintf("?category=%s&product=%s&version=%s&dlgType=%s", category, slug, majorVersion, dlgType)
 	var res *http.Response
-	res, err = c.HttpClient.Get(dlgListURL + search_string)
+	res, err = c.getCached(ctx, c.endpoints().DlgList+search_string)
 	if err != nil {return}
 	defer res.Body.Close()
 
-	err = c.validateResponseSlugCategoryVersion(slug, category, majorVersion, *res)
+	err = c.validateResponseSlugCategoryVersion(ctx, slug, category, majorVersion, res)
 	if err != nil {return}
 
 	var dlgEditions DlgEditions
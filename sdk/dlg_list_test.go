@@ -10,6 +10,8 @@ import (
 )
 
 func TestGetDlgListDownloads(t *testing.T) {
+	skipUnlessLive(t)
+
 	var dlgEditions []DlgEditionsLists
 	dlgEditions, err = basicClient.GetDlgEditionsList("vmware_vsphere", "7_0", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -17,6 +19,8 @@ func TestGetDlgListDownloads(t *testing.T) {
 }
 
 func TestGetDlgListDrivers(t *testing.T) {
+	skipUnlessLive(t)
+
 	var dlgEditions []DlgEditionsLists
 	dlgEditions, err = basicClient.GetDlgEditionsList("vmware_vsphere", "7_0", "DRIVERS_TOOLS")
 	assert.Nil(t, err)
@@ -24,6 +28,8 @@ func TestGetDlgListDrivers(t *testing.T) {
 }
 
 func TestGetDlgListCustomISO(t *testing.T) {
+	skipUnlessLive(t)
+
 	var dlgEditions []DlgEditionsLists
 	dlgEditions, err = basicClient.GetDlgEditionsList("vmware_vsphere", "7_0", "CUSTOM_ISO")
 	assert.Nil(t, err)
@@ -31,6 +37,8 @@ func TestGetDlgListCustomISO(t *testing.T) {
 }
 
 func TestGetDlgListAddons(t *testing.T) {
+	skipUnlessLive(t)
+
 	var dlgEditions []DlgEditionsLists
 	dlgEditions, err = basicClient.GetDlgEditionsList("vmware_vsphere", "7_0", "ADDONS")
 	assert.Nil(t, err)
@@ -38,16 +46,18 @@ func TestGetDlgListAddons(t *testing.T) {
 }
 
 func TestGetDlgListInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var dlgEditions []DlgEditionsLists
-	dlgEditions, err = basicClient.GetDlgEditionsList("mware_tools", "11_x", "PRODUCT_BINARY")
+	dlgEditions, err = client.GetDlgEditionsList("mware_tools", "11_x", "PRODUCT_BINARY")
 	assert.NotNil(t, err)
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, dlgEditions, "Expected response to be empty")
 }
 
 func TestGetDlgListInvalidVersion(t *testing.T) {
+	client := newFakeClient(t)
 	var dlgEditions []DlgEditionsLists
-	dlgEditions, err = basicClient.GetDlgEditionsList("vmware_tools", "99_x", "PRODUCT_BINARY")
+	dlgEditions, err = client.GetDlgEditionsList("vmware_tools", "99_x", "PRODUCT_BINARY")
 	assert.NotNil(t, err)
 	assert.ErrorIs(t, err, ErrorInvalidVersion)
 	assert.Empty(t, dlgEditions, "Expected response to be empty")
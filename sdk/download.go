@@ -5,6 +5,7 @@ package sdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -31,45 +32,41 @@ type AuthorizedDownload struct {
 }
 
 const (
-	downloadURL = baseURL + "/channel/api/v1.0/dlg/download"
+	downloadPath = "/channel/api/v1.0/dlg/download"
 )
 
 var ErrorInvalidDownloadPayload = errors.New("download: invalid download payload")
 
 func (c *Client) GenerateDownloadPayload(slug, subProduct, version, fileName, dlgType string, acceptEula bool) (data []DownloadPayload, err error) {
-	if err = c.CheckLoggedIn(); err != nil {
-		return
-	}
+	return c.GenerateDownloadPayloadCtx(context.Background(), slug, subProduct, version, fileName, dlgType, acceptEula)
+}
 
-	if err = c.EnsureProductDetailMap(); err != nil {
+func (c *Client) GenerateDownloadPayloadCtx(ctx context.Context, slug, subProduct, version, fileName, dlgType string, acceptEula bool) (data []DownloadPayload, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDownloadPayload", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrVersion, version), attr(AttrFileName, fileName), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
 		return
 	}
 
-	if _, ok := ProductDetailMap[slug]; !ok {
-		err = ErrorInvalidSlug
+	if _, err = c.lookupProduct(ctx, slug); err != nil {
 		return
 	}
 
 	var productID string
 	var apiVersions APIVersions
-	productID, apiVersions, err = c.GetDlgProduct(slug, subProduct, version, dlgType)
+	productID, apiVersions, err = c.GetDlgProductCtx(ctx, slug, subProduct, version, dlgType)
 	if err != nil {
 		return
 	}
 
 	var dlgHeader DlgHeader
-	dlgHeader, err = c.GetDlgHeader(apiVersions.Code, productID)
+	dlgHeader, err = c.GetDlgHeaderCtx(ctx, apiVersions.Code, productID)
 	if err != nil {
 		return
 	}
-	if dlgHeader.Dlg.Type == "OEM Addons" {
-		dlgHeader.Dlg.Type = "Drivers & Tools"
-	} else {
-		dlgHeader.Dlg.Type = strings.Replace(dlgHeader.Dlg.Type, "amp;", "",1)
-	}
 
 	var downloadDetails FoundDownload
-	downloadDetails, err = c.FindDlgDetails(apiVersions.Code, productID, fileName)
+	downloadDetails, err = c.FindDlgDetailsCtx(ctx, apiVersions.Code, productID, fileName)
 	if err != nil {
 		return
 	}
@@ -84,7 +81,7 @@ func (c *Client) GenerateDownloadPayload(slug, subProduct, version, fileName, dl
 			err = ErrorEulaUnaccepted
 			return
 		} else {
-			err = c.AcceptEula(apiVersions.Code, productID)
+			err = c.AcceptEulaCtx(ctx, apiVersions.Code, productID)
 			if err != nil {
 				return
 			}
@@ -92,29 +89,44 @@ func (c *Client) GenerateDownloadPayload(slug, subProduct, version, fileName, dl
 	}
 
 	for _, downloadFile := range downloadDetails.DownloadDetails {
-		downloadPayload := DownloadPayload{
-			Locale:        "en_US",
-			DownloadGroup: apiVersions.Code,
-			ProductId:     productID,
-			Md5checksum:   downloadFile.Md5Checksum,
-			TagId:         dlgHeader.Dlg.TagID,
-			UUId:          downloadFile.UUID,
-			DlgType:       dlgHeader.Dlg.Type,
-			ProductFamily: dlgHeader.Product.Name,
-			ReleaseDate:   downloadFile.ReleaseDate,
-			DlgVersion:    downloadFile.Version,
-			IsBetaFlow:    false,
-		}
+		data = append(data, newDownloadPayload(dlgHeader, apiVersions.Code, productID, downloadFile))
+	}
 
-		data = append(data, downloadPayload)
+	return
+}
 
+// newDownloadPayload builds the request for the download link of a file in a download group
+func newDownloadPayload(dlgHeader DlgHeader, downloadGroup, productID string, downloadFile DownloadDetails) DownloadPayload {
+	dlgType := dlgHeader.Dlg.Type
+	if dlgType == "OEM Addons" {
+		dlgType = "Drivers & Tools"
+	} else {
+		dlgType = strings.Replace(dlgType, "amp;", "", 1)
 	}
 
-	return
+	return DownloadPayload{
+		Locale:        "en_US",
+		DownloadGroup: downloadGroup,
+		ProductId:     productID,
+		Md5checksum:   downloadFile.Md5Checksum,
+		TagId:         dlgHeader.Dlg.TagID,
+		UUId:          downloadFile.UUID,
+		DlgType:       dlgType,
+		ProductFamily: dlgHeader.Product.Name,
+		ReleaseDate:   downloadFile.ReleaseDate,
+		DlgVersion:    downloadFile.Version,
+		IsBetaFlow:    false,
+	}
 }
 
 func (c *Client) FetchDownloadLink(downloadPayload DownloadPayload) (data AuthorizedDownload, err error) {
-	if err = c.CheckLoggedIn(); err != nil {
+	return c.FetchDownloadLinkCtx(context.Background(), downloadPayload)
+}
+
+func (c *Client) FetchDownloadLinkCtx(ctx context.Context, downloadPayload DownloadPayload) (data AuthorizedDownload, err error) {
+	ctx, span := c.startSpan(ctx, "FetchDownloadLink", attr(AttrDownloadGroup, downloadPayload.DownloadGroup), attr(AttrProductID, downloadPayload.ProductId))
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
 		return
 	}
 
@@ -122,14 +134,14 @@ func (c *Client) FetchDownloadLink(downloadPayload DownloadPayload) (data Author
 	payload := bytes.NewBuffer(postJson)
 
 	var req *http.Request
-	req, err = http.NewRequest("POST", downloadURL, payload)
+	req, err = http.NewRequestWithContext(ctx, "POST", c.endpoints().Download, payload)
 	if err != nil {
 		return
 	}
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("X-XSRF-TOKEN", c.XsrfToken)
+	req.Header.Add("X-XSRF-TOKEN", c.CurrentXsrfToken())
 	var res *http.Response
-	res, err = c.HttpClient.Do(req)
+	res, err = c.do(req)
 	if err != nil {
 		return
 	}
@@ -138,9 +150,9 @@ func (c *Client) FetchDownloadLink(downloadPayload DownloadPayload) (data Author
 	if res.StatusCode == 200 {
 		err = json.NewDecoder(res.Body).Decode(&data)
 	} else if res.StatusCode == 400 {
-		err = ErrorInvalidDownloadPayload
+		err = newAPIError(res, ErrorInvalidDownloadPayload)
 	} else {
-		err = ErrorNon200Response
+		err = c.validateResponseNon200(res)
 	}
 
 	return
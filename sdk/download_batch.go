@@ -0,0 +1,124 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+type BatchOptions struct {
+	// Number of files downloaded at the same time. Defaults to 4.
+	Concurrency int
+	// Progress is called with the file name as data is written. It is called from multiple goroutines.
+	Progress func(fileName string, written, total int64)
+	// Quarantine files failing checksum verification, see DownloadOptions
+	Quarantine bool
+	// Checksums keyed by file UUID, which are verified instead of the MD5 checksum in the payload
+	Checksums map[string]Checksums
+}
+
+// BatchResult holds the outcome of a single file from DownloadBatch
+type BatchResult struct {
+	Payload  DownloadPayload
+	FileName string
+	Path     string
+	Err      error
+}
+
+// DownloadBatch fetches a download link for every payload, e.g. as returned by GenerateDownloadPayload
+// for a file glob, and downloads the files into destDir using a bounded pool of workers.
+// A failure only affects its own file, so every payload has a result in the same order as the input.
+// Files are verified against the MD5 checksum carried in the payload, unless opts has checksums for the file.
+// destDir is created when it does not exist yet.
+func (c *Client) DownloadBatch(ctx context.Context, payloads []DownloadPayload, destDir string, opts BatchOptions) (results []BatchResult) {
+	ctx, span := c.startSpan(ctx, "DownloadBatch", attr(AttrFileCount, len(payloads)))
+	defer func() { endSpan(span, BatchError(results)) }()
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results = make([]BatchResult, len(payloads))
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		for i := range payloads {
+			results[i] = BatchResult{Payload: payloads[i], Err: err}
+		}
+		return
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.downloadPayload(ctx, payloads[i], destDir, opts)
+			}
+		}()
+	}
+
+	for i := range payloads {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return
+}
+
+func (c *Client) downloadPayload(ctx context.Context, payload DownloadPayload, destDir string, opts BatchOptions) (result BatchResult) {
+	result.Payload = payload
+
+	if result.Err = ctx.Err(); result.Err != nil {
+		return
+	}
+
+	var authorizedDownload AuthorizedDownload
+	authorizedDownload, result.Err = c.FetchDownloadLinkCtx(ctx, payload)
+	if result.Err != nil {
+		return
+	}
+	result.FileName = authorizedDownload.FileName
+
+	downloadOpts := DownloadOptions{
+		Checksums:  Checksums{Md5: payload.Md5checksum},
+		Quarantine: opts.Quarantine,
+	}
+	if checksums, ok := opts.Checksums[payload.UUId]; ok {
+		downloadOpts.Checksums = checksums
+	}
+	if opts.Progress != nil {
+		downloadOpts.Progress = func(written, total int64) {
+			opts.Progress(authorizedDownload.FileName, written, total)
+		}
+	}
+
+	result.Path, result.Err = c.Download(ctx, authorizedDownload, destDir, downloadOpts)
+	return
+}
+
+// BatchError joins the errors of all failed downloads, returning nil when every file succeeded
+func BatchError(results []BatchResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		name := result.FileName
+		if name == "" {
+			name = result.Payload.UUId
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, result.Err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadBatch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "12.3.0", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.Len(t, payloads, 3)
+
+	// A payload which cannot be fetched must not stop the other downloads
+	invalid := payloads[0]
+	invalid.UUId = "invalid"
+	payloads = append([]DownloadPayload{invalid}, payloads...)
+
+	var mu sync.Mutex
+	progressed := make(map[string]bool)
+	results := client.DownloadBatch(context.Background(), payloads, t.TempDir(), BatchOptions{
+		Concurrency: 2,
+		Progress: func(fileName string, written, total int64) {
+			mu.Lock()
+			progressed[fileName] = true
+			mu.Unlock()
+		},
+	})
+
+	require.Len(t, results, 4)
+	assert.ErrorIs(t, results[0].Err, ErrorInvalidDownloadPayload)
+	for _, result := range results[1:] {
+		assert.Nil(t, result.Err)
+		assert.FileExists(t, result.Path)
+		assert.True(t, progressed[result.FileName])
+	}
+
+	err = BatchError(results)
+	assert.ErrorIs(t, err, ErrorInvalidDownloadPayload)
+	assert.Contains(t, err.Error(), "invalid")
+}
+
+func TestDownloadBatchCancelled(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "12.3.0", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := client.DownloadBatch(ctx, payloads, t.TempDir(), BatchOptions{})
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+	assert.Nil(t, BatchError(nil))
+}
+
+func TestDownloadBatchMissingDestDir(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "12.3.0", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.Len(t, payloads, 3)
+
+	destDir := filepath.Join(t.TempDir(), "out")
+	results := client.DownloadBatch(context.Background(), payloads, destDir, BatchOptions{})
+	require.Nil(t, BatchError(results))
+	for _, result := range results {
+		assert.Equal(t, filepath.Join(destDir, result.FileName), result.Path)
+		assert.FileExists(t, result.Path)
+	}
+
+	entries, err := os.ReadDir(destDir)
+	require.Nil(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestDownloadBatchDestDirIsFile(t *testing.T) {
+	client := NewClient(ClientOptions{})
+
+	destDir := filepath.Join(t.TempDir(), "out")
+	require.Nil(t, os.WriteFile(destDir, nil, 0644))
+
+	results := client.DownloadBatch(context.Background(), []DownloadPayload{{UUId: "a"}, {UUId: "b"}}, destDir, BatchOptions{})
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.NotNil(t, result.Err)
+		assert.Empty(t, result.Path)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	partialSuffix    = ".partial"
+	quarantineSuffix = ".corrupt"
+)
+
+// Checksums of a file, as published in DownloadDetails. Empty values are ignored.
+type Checksums struct {
+	Sha256 string
+	Sha1   string
+	Md5    string
+}
+
+type DownloadOptions struct {
+	// Checksums to verify the file against. Only the strongest checksum available is checked.
+	Checksums Checksums
+	// Progress is called after every write with the bytes written so far, including any resumed
+	// part of the file. total is -1 when the server does not return the size.
+	Progress func(written, total int64)
+	// Quarantine renames files which fail verification to <file>.corrupt instead of deleting them
+	Quarantine bool
+}
+
+var (
+	ErrorChecksumMismatch = errors.New("download: checksum of downloaded file does not match")
+	ErrorDownloadResponse = errors.New("download: server did not return the file")
+)
+
+func ChecksumsFromDetails(details DownloadDetails) Checksums {
+	return Checksums{
+		Sha256: details.Sha256Checksum,
+		Sha1:   details.Sha1Checksum,
+		Md5:    details.Md5Checksum,
+	}
+}
+
+// Return a hash for the strongest checksum available, or nil when there is nothing to verify
+func (c Checksums) hasher() (h hash.Hash, expected string) {
+	switch {
+	case c.Sha256 != "":
+		return sha256.New(), c.Sha256
+	case c.Sha1 != "":
+		return sha1.New(), c.Sha1
+	case c.Md5 != "":
+		return md5.New(), c.Md5
+	}
+	return nil, ""
+}
+
+// Download streams the file behind an authorized download link to dest, which can either be a
+// file path or an existing directory. Data is written to <file>.partial first, so an interrupted
+// download is resumed with a range request on the next call. The complete file is verified
+// against the checksums in opts before being moved into place. The path of the file is returned.
+func (c *Client) Download(ctx context.Context, authorizedDownload AuthorizedDownload, dest string, opts DownloadOptions) (path string, err error) {
+	ctx, span := c.startSpan(ctx, "Download", attr(AttrFileName, authorizedDownload.FileName))
+	defer func() { endSpan(span, err) }()
+	path = dest
+	if info, statErr := os.Stat(dest); statErr == nil && info.IsDir() {
+		path = filepath.Join(dest, filepath.Base(authorizedDownload.FileName))
+	}
+
+	// Skip the download when a verified copy is already present
+	if _, expected := opts.Checksums.hasher(); expected != "" {
+		if verifyErr := verifyFile(path, opts.Checksums); verifyErr == nil {
+			return
+		}
+	}
+
+	partialPath := path + partialSuffix
+	var partial *os.File
+	partial, err = os.OpenFile(partialPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return
+	}
+	defer partial.Close()
+
+	var offset int64
+	if offset, err = partial.Seek(0, io.SeekEnd); err != nil {
+		return
+	}
+
+	if err = c.fetchToFile(ctx, authorizedDownload.DownloadURL, partial, offset, opts.Progress); err != nil {
+		return
+	}
+	if err = partial.Close(); err != nil {
+		return
+	}
+
+	if err = verifyFile(partialPath, opts.Checksums); err != nil {
+		if errors.Is(err, ErrorChecksumMismatch) {
+			discardFile(partialPath, path, opts.Quarantine)
+		}
+		return
+	}
+
+	err = os.Rename(partialPath, path)
+	return
+}
+
+// Write the remote file to out, requesting the remainder of the file when offset is not zero
+func (c *Client) fetchToFile(ctx context.Context, downloadURL string, out *os.File, offset int64, progress func(written, total int64)) (err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	var res *http.Response
+	res, err = c.HttpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	// Start again when the partial file does not belong to the remote file, e.g. it changed upstream
+	restart := func() error {
+		res.Body.Close()
+		if err := truncateFile(out); err != nil {
+			return err
+		}
+		return c.fetchToFile(ctx, downloadURL, out, 0, progress)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusPartialContent:
+		if start, _, ok := parseContentRange(res.Header.Get("Content-Range")); !ok || start != offset {
+			return restart()
+		}
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is already complete, unless it is larger than the remote file
+		if _, complete, ok := parseContentRange(res.Header.Get("Content-Range")); !ok || complete != offset {
+			return restart()
+		}
+		return
+	case res.StatusCode == http.StatusOK:
+		// Range not supported, start from the beginning
+		if offset > 0 {
+			if err = truncateFile(out); err != nil {
+				return
+			}
+			offset = 0
+		}
+	default:
+		err = newAPIError(res, ErrorDownloadResponse)
+		return
+	}
+
+	total := int64(-1)
+	if res.ContentLength >= 0 {
+		total = offset + res.ContentLength
+	}
+
+	var w io.Writer = out
+	if progress != nil {
+		w = &progressWriter{w: out, written: offset, total: total, progress: progress}
+	}
+	_, err = io.Copy(w, res.Body)
+	return
+}
+
+func truncateFile(f *os.File) (err error) {
+	if err = f.Truncate(0); err != nil {
+		return
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return
+}
+
+// parseContentRange returns the first byte and the complete length of a Content-Range header, e.g.
+// "bytes 100-199/200" or "bytes */200" as sent with a 416 response. complete is -1 when unknown.
+func parseContentRange(value string) (start, complete int64, ok bool) {
+	rangeSpec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return
+	}
+	byteRange, length, found := strings.Cut(rangeSpec, "/")
+	if !found {
+		return
+	}
+
+	var err error
+	complete = -1
+	if length != "*" {
+		if complete, err = strconv.ParseInt(length, 10, 64); err != nil {
+			return
+		}
+	}
+
+	if byteRange == "*" {
+		start = -1
+		return start, complete, complete >= 0
+	}
+	first, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return
+	}
+	return start, complete, true
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (n int, err error) {
+	n, err = p.w.Write(b)
+	p.written += int64(n)
+	p.progress(p.written, p.total)
+	return
+}
+
+func verifyFile(path string, checksums Checksums) (err error) {
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		return
+	}
+	defer f.Close()
+
+	h, expected := checksums.hasher()
+	if h == nil {
+		return
+	}
+	if _, err = io.Copy(h, f); err != nil {
+		return
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		err = fmt.Errorf("%w: %s expected %s, got %s", ErrorChecksumMismatch, filepath.Base(path), expected, actual)
+	}
+	return
+}
+
+func discardFile(partialPath, path string, quarantine bool) {
+	if quarantine {
+		os.Rename(partialPath, path+quarantineSuffix)
+	} else {
+		os.Remove(partialPath)
+	}
+}
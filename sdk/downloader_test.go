@@ -0,0 +1,200 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Resolve a single fake file and return its download link and published checksums
+func fakeAuthorizedDownload(t *testing.T, client *Client) (AuthorizedDownload, Checksums) {
+	t.Helper()
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.1.1", "VMware-Tools-darwin-*.tar.gz", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.Len(t, payloads, 1)
+
+	details, err := client.FindDlgDetails(payloads[0].DownloadGroup, payloads[0].ProductId, "VMware-Tools-darwin-*.tar.gz")
+	require.Nil(t, err)
+
+	authorizedDownload, err := client.FetchDownloadLink(payloads[0])
+	require.Nil(t, err)
+	return authorizedDownload, ChecksumsFromDetails(details.DownloadDetails[0])
+}
+
+func TestDownload(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+	authorizedDownload, checksums := fakeAuthorizedDownload(t, client)
+
+	var written, total int64
+	dir := t.TempDir()
+	path, err := client.Download(context.Background(), authorizedDownload, dir, DownloadOptions{
+		Checksums: checksums,
+		Progress:  func(w, t int64) { written, total = w, t },
+	})
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, authorizedDownload.FileName), path)
+	assert.Greater(t, total, int64(0))
+	assert.Equal(t, total, written)
+	assert.NoFileExists(t, path+partialSuffix)
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, int(total), len(content))
+}
+
+func TestDownloadResume(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+	authorizedDownload, checksums := fakeAuthorizedDownload(t, client)
+
+	// Download the first part of the file, as if an earlier run was interrupted
+	res, err := client.HttpClient.Get(authorizedDownload.DownloadURL)
+	require.Nil(t, err)
+	full := make([]byte, res.ContentLength)
+	_, err = io.ReadFull(res.Body, full)
+	res.Body.Close()
+	require.Nil(t, err)
+	path := filepath.Join(t.TempDir(), authorizedDownload.FileName)
+	require.Nil(t, os.WriteFile(path+partialSuffix, full[:100], 0644))
+
+	var ranges []string
+	transport := client.HttpClient.Transport
+	client.HttpClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ranges = append(ranges, req.Header.Get("Range"))
+		if transport == nil {
+			return http.DefaultTransport.RoundTrip(req)
+		}
+		return transport.RoundTrip(req)
+	})
+
+	_, err = client.Download(context.Background(), authorizedDownload, path, DownloadOptions{Checksums: checksums})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"bytes=100-"}, ranges)
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, full, content)
+
+	// A verified file is not downloaded again
+	_, err = client.Download(context.Background(), authorizedDownload, path, DownloadOptions{Checksums: checksums})
+	require.Nil(t, err)
+	assert.Len(t, ranges, 1)
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+	authorizedDownload, _ := fakeAuthorizedDownload(t, client)
+
+	dir := t.TempDir()
+	path, err := client.Download(context.Background(), authorizedDownload, dir, DownloadOptions{
+		Checksums: Checksums{Sha256: strings.Repeat("0", 64)},
+	})
+	assert.ErrorIs(t, err, ErrorChecksumMismatch)
+	assert.NoFileExists(t, path)
+	assert.NoFileExists(t, path+partialSuffix)
+
+	_, err = client.Download(context.Background(), authorizedDownload, dir, DownloadOptions{
+		Checksums:  Checksums{Md5: strings.Repeat("0", 32)},
+		Quarantine: true,
+	})
+	assert.ErrorIs(t, err, ErrorChecksumMismatch)
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, path+quarantineSuffix)
+}
+
+// Serve content, answering range requests with the given handler
+func newRangeServer(t *testing.T, content string, handleRange func(w http.ResponseWriter, r *http.Request)) (client *Client, authorizedDownload AuthorizedDownload, requests *int) {
+	t.Helper()
+
+	requests = new(int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.Header.Get("Range") != "" {
+			handleRange(w, r)
+			return
+		}
+		io.WriteString(w, content)
+	}))
+	t.Cleanup(srv.Close)
+
+	client = NewClient(ClientOptions{})
+	authorizedDownload = AuthorizedDownload{DownloadURL: srv.URL + "/file.bin", FileName: "file.bin"}
+	return
+}
+
+func TestDownloadResumeMismatchedRange(t *testing.T) {
+	content := strings.Repeat("remote", 50)
+	// The server ignores the requested start and sends the whole file
+	client, authorizedDownload, requests := newRangeServer(t, content, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		io.WriteString(w, content)
+	})
+
+	path := filepath.Join(t.TempDir(), "file.bin")
+	require.Nil(t, os.WriteFile(path+partialSuffix, []byte(strings.Repeat("x", 100)), 0644))
+
+	_, err := client.Download(context.Background(), authorizedDownload, path, DownloadOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 2, *requests)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, content, string(data))
+}
+
+func TestDownloadResumeRangeNotSatisfiable(t *testing.T) {
+	content := strings.Repeat("remote", 50)
+	client, authorizedDownload, requests := newRangeServer(t, content, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	})
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	// A complete partial file is moved into place
+	require.Nil(t, os.WriteFile(path+partialSuffix, []byte(content), 0644))
+	_, err := client.Download(context.Background(), authorizedDownload, path, DownloadOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 1, *requests)
+
+	// A partial file larger than the remote file is downloaded again
+	require.Nil(t, os.Remove(path))
+	require.Nil(t, os.WriteFile(path+partialSuffix, []byte(content+"stale"), 0644))
+	_, err = client.Download(context.Background(), authorizedDownload, path, DownloadOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 3, *requests)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, content, string(data))
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, complete, ok := parseContentRange("bytes 100-199/200")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(200), complete)
+
+	start, complete, ok = parseContentRange("bytes 100-199/*")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(-1), complete)
+
+	_, complete, ok = parseContentRange("bytes */200")
+	assert.True(t, ok)
+	assert.Equal(t, int64(200), complete)
+
+	for _, value := range []string{"", "bytes */*", "items 0-1/2", "bytes 0-1", "bytes x-1/2"} {
+		_, _, ok = parseContentRange(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import "strings"
+
+const (
+	DefaultBaseURL = "https://customerconnect.vmware.com"
+	DefaultAuthURL = "https://auth.vmware.com/oam/server/auth_cred_submit?Auth-AppID=WMVMWR"
+)
+
+// Endpoints holds every URL the SDK talks to. Only BaseURL and AuthURL need to be set,
+// all other endpoints are derived from BaseURL unless explicitly overridden.
+type Endpoints struct {
+	BaseURL string
+	AuthURL string
+
+	Init                    string
+	SSO                     string
+	Products                string
+	MajorVersions           string
+	DlgList                 string
+	DlgHeader               string
+	DlgDetailsAuthenticated string
+	DlgDetailsPublic        string
+	Download                string
+	Eula                    string
+	AccountInfo             string
+	CurrentUser             string
+}
+
+// DefaultEndpoints returns the endpoints of the live Customer Connect service
+func DefaultEndpoints() Endpoints {
+	return NewEndpoints(DefaultBaseURL, DefaultAuthURL)
+}
+
+// NewEndpoints derives the full endpoint set from a base URL, e.g. a mirror or a local fake server.
+// authURL is the credential submission endpoint, which lives on a separate host in production.
+func NewEndpoints(baseURL, authURL string) Endpoints {
+	return Endpoints{BaseURL: baseURL, AuthURL: authURL}.withDefaults()
+}
+
+// Fill in any endpoints which have not been explicitly set
+func (e Endpoints) withDefaults() Endpoints {
+	if e.BaseURL == "" {
+		e.BaseURL = DefaultBaseURL
+	}
+	e.BaseURL = strings.TrimSuffix(e.BaseURL, "/")
+	if e.AuthURL == "" {
+		e.AuthURL = DefaultAuthURL
+	}
+
+	defaults := []struct {
+		field *string
+		path  string
+	}{
+		{&e.Init, initPath},
+		{&e.SSO, ssoPath},
+		{&e.Products, productsPath},
+		{&e.MajorVersions, majorVersionsPath},
+		{&e.DlgList, dlgListPath},
+		{&e.DlgHeader, dlgHeaderPath},
+		{&e.DlgDetailsAuthenticated, dlgDetailsPathAuthenticated},
+		{&e.DlgDetailsPublic, dlgDetailsPathPublic},
+		{&e.Download, downloadPath},
+		{&e.Eula, eulaPath},
+		{&e.AccountInfo, accountInfoPath},
+		{&e.CurrentUser, currentUserPath},
+	}
+	for _, d := range defaults {
+		if *d.field == "" {
+			*d.field = e.BaseURL + d.path
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEndpointsDerivesFromBaseURL(t *testing.T) {
+	endpoints := NewEndpoints("http://127.0.0.1:8080/", "http://127.0.0.1:8080/auth")
+	assert.Equal(t, "http://127.0.0.1:8080", endpoints.BaseURL)
+	assert.Equal(t, "http://127.0.0.1:8080/auth", endpoints.AuthURL)
+	assert.Equal(t, "http://127.0.0.1:8080"+productsPath, endpoints.Products)
+	assert.Equal(t, "http://127.0.0.1:8080"+dlgDetailsPathPublic, endpoints.DlgDetailsPublic)
+}
+
+func TestEndpointsOverride(t *testing.T) {
+	endpoints := Endpoints{BaseURL: "http://mirror", Download: "http://proxy/download"}.withDefaults()
+	assert.Equal(t, "http://proxy/download", endpoints.Download)
+	assert.Equal(t, "http://mirror"+eulaPath, endpoints.Eula)
+	assert.Equal(t, DefaultAuthURL, endpoints.AuthURL)
+}
+
+func TestClientDefaultEndpoints(t *testing.T) {
+	assert.Equal(t, DefaultEndpoints(), basicClient.endpoints())
+
+	client := NewClient(ClientOptions{Endpoints: NewEndpoints("http://mirror", "")})
+	assert.Equal(t, "http://mirror"+accountInfoPath, client.endpoints().AccountInfo)
+}
@@ -4,24 +4,31 @@
 package sdk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
 const (
-	eulaURL = baseURL + "/channel/api/v1.0/dlg/eula/accept"
+	eulaPath = "/channel/api/v1.0/dlg/eula/accept"
 )
 
 var ErrorEulaInputs = errors.New("eula: downloadGroup or productId invalid")
 
 func (c *Client) FetchEulaUrl(downloadGroup, productId string) (url string, err error) {
-	if err = c.CheckLoggedIn(); err != nil {
+	return c.FetchEulaUrlCtx(context.Background(), downloadGroup, productId)
+}
+
+func (c *Client) FetchEulaUrlCtx(ctx context.Context, downloadGroup, productId string) (url string, err error) {
+	ctx, span := c.startSpan(ctx, "FetchEulaUrl", attr(AttrDownloadGroup, downloadGroup), attr(AttrProductID, productId))
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
 		return
 	}
 
 	var dlgDetails DlgDetails
-	dlgDetails, err = c.GetDlgDetails(downloadGroup, productId)
+	dlgDetails, err = c.GetDlgDetailsCtx(ctx, downloadGroup, productId)
 	if err != nil {
 		return
 	}
@@ -32,24 +39,29 @@ func (c *Client) FetchEulaUrl(downloadGroup, productId string) (url string, err
 }
 
 func (c *Client) AcceptEula(downloadGroup, productId string) (err error) {
-	if err = c.CheckLoggedIn(); err != nil {
+	return c.AcceptEulaCtx(context.Background(), downloadGroup, productId)
+}
+
+func (c *Client) AcceptEulaCtx(ctx context.Context, downloadGroup, productId string) (err error) {
+	ctx, span := c.startSpan(ctx, "AcceptEula", attr(AttrDownloadGroup, downloadGroup), attr(AttrProductID, productId))
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
 		return
 	}
 
 	search_string := fmt.Sprintf("?downloadGroup=%s&productId=%s", downloadGroup, productId)
 	var res *http.Response
-	res, err = c.HttpClient.Get(eulaURL + search_string)
+	res, err = c.get(ctx, c.endpoints().Eula+search_string)
 	if err != nil {
 		return
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode == 400 {
-		err = ErrorEulaInputs
+		err = newAPIError(res, ErrorEulaInputs)
 		return
-	} else if res.StatusCode != 200 {
-		err = ErrorNon200Response
 	}
+	err = c.validateResponseNon200(res)
 
 	return
 }
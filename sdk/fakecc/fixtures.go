@@ -0,0 +1,83 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package fakecc
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+)
+
+// Credentials accepted by the default fixtures
+const (
+	Username = "user@example.com"
+	Password = "secret"
+)
+
+//go:embed fixtures/default.json
+var defaultFixtures []byte
+
+// Fixtures holds the responses served by the fake server. Responses are stored as raw JSON
+// in the same shape as returned by Customer Connect, keyed by the query parameters of the request.
+type Fixtures struct {
+	Users []User `json:"users"`
+
+	// getProductsAtoZ response
+	Products json.RawMessage `json:"products"`
+	// getProductHeader responses keyed by product slug
+	ProductHeaders map[string]json.RawMessage `json:"productHeaders"`
+	// getRelatedDLGList responses keyed by <product>/<version>/<dlgType>
+	DlgLists map[string]json.RawMessage `json:"dlgLists"`
+	// getDLGHeader responses keyed by <downloadGroup>/<productId>
+	DlgHeaders map[string]json.RawMessage `json:"dlgHeaders"`
+	// dlg/details responses keyed by <downloadGroup>/<productId>
+	DlgDetails map[string]DlgDetails `json:"dlgDetails"`
+	// Downloadable file contents keyed by file UUID
+	Files map[string]File `json:"files"`
+}
+
+type User struct {
+	Username    string          `json:"username"`
+	Password    string          `json:"password"`
+	Firstname   string          `json:"firstname"`
+	Lastname    string          `json:"lastname"`
+	AccountInfo json.RawMessage `json:"accountInfo"`
+}
+
+// DlgDetails describes a download group. The public endpoint only returns the files,
+// the authenticated endpoint adds the entitlement and EULA state.
+type DlgDetails struct {
+	DownloadFiles      json.RawMessage `json:"downloadFiles"`
+	EligibleToDownload bool            `json:"eligibleToDownload"`
+	EulaAccepted       bool            `json:"eulaAccepted"`
+	EulaURL            string          `json:"eulaURL"`
+}
+
+type File struct {
+	FileName string `json:"fileName"`
+	Content  string `json:"content"`
+}
+
+// DefaultFixtures returns a small catalog containing VMware Tools and vSphere ESXi
+func DefaultFixtures() *Fixtures {
+	fixtures, err := ParseFixtures(defaultFixtures)
+	if err != nil {
+		panic(err)
+	}
+	return fixtures
+}
+
+func ParseFixtures(data []byte) (fixtures *Fixtures, err error) {
+	fixtures = &Fixtures{}
+	err = json.Unmarshal(data, fixtures)
+	return
+}
+
+func LoadFixtures(path string) (fixtures *Fixtures, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return
+	}
+	return ParseFixtures(data)
+}
@@ -0,0 +1,415 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+// Package fakecc provides an in-process fake of the Customer Connect API, so code built on
+// the SDK can be tested without network access or credentials.
+//
+//	srv := fakecc.NewServer(nil)
+//	defer srv.Close()
+//	opts := sdk.ClientOptions{Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL())}
+//	client, err := sdk.LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+package fakecc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	initPath                    = "/web/vmware/login"
+	ssoPath                     = "/vmwauth/saml/SSO"
+	authPath                    = "/oam/server/auth_cred_submit"
+	loginPagePath               = "/login"
+	productsPath                = "/channel/public/api/v1.0/products/getProductsAtoZ"
+	majorVersionsPath           = "/channel/public/api/v1.0/products/getProductHeader"
+	dlgListPath                 = "/channel/public/api/v1.0/products/getRelatedDLGList"
+	dlgHeaderPath               = "/channel/public/api/v1.0/products/getDLGHeader"
+	dlgDetailsPathPublic        = "/channel/public/api/v1.0/dlg/details"
+	dlgDetailsPathAuthenticated = "/channel/api/v1.0/dlg/details"
+	eulaPath                    = "/channel/api/v1.0/dlg/eula/accept"
+	downloadPath                = "/channel/api/v1.0/dlg/download"
+	accountInfoPath             = "/channel/api/v1.0/ems/accountinfo"
+	currentUserPath             = "/vmwauth/loggedinuser"
+	filesPath                   = "/files/"
+
+	SessionCookie = "JSESSIONID"
+	XsrfCookie    = "XSRF-TOKEN"
+)
+
+const samlPage = `<html><body onload="document.forms[0].submit()">
+<form method="POST" action="%s">
+<input type="hidden" name="SAMLResponse" value="%s"/>
+</form></body></html>`
+
+type Server struct {
+	*httptest.Server
+
+	fixtures *Fixtures
+
+	mu       sync.Mutex
+	tokens   map[string]string // SAML token to username
+	sessions map[string]*session
+	requests map[string]int
+	failures map[string][]int
+	// EULAs accepted by each user, keyed by <downloadGroup>/<productId>
+	eulas map[string]map[string]bool
+}
+
+type session struct {
+	user *User
+	xsrf string
+}
+
+// NewServer starts a fake Customer Connect server. When fixtures is nil DefaultFixtures is used.
+func NewServer(fixtures *Fixtures) *Server {
+	s := newServer(fixtures)
+	s.Server = httptest.NewServer(s.count(s.mux()))
+	return s
+}
+
+// NewTLSServer starts a fake server using TLS. Its certificate is only trusted by s.Client()
+// or by clients configured with the certificate, see Server.Certificate.
+func NewTLSServer(fixtures *Fixtures) *Server {
+	s := newServer(fixtures)
+	s.Server = httptest.NewTLSServer(s.count(s.mux()))
+	return s
+}
+
+func newServer(fixtures *Fixtures) *Server {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	return &Server{
+		fixtures: fixtures,
+		tokens:   make(map[string]string),
+		sessions: make(map[string]*session),
+		requests: make(map[string]int),
+		failures: make(map[string][]int),
+		eulas:    make(map[string]map[string]bool),
+	}
+}
+
+func (s *Server) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(initPath, s.handleInit)
+	mux.HandleFunc(authPath, s.handleAuth)
+	mux.HandleFunc(loginPagePath, s.handleLoginPage)
+	mux.HandleFunc(ssoPath, s.handleSSO)
+	mux.HandleFunc(productsPath, s.handleProducts)
+	mux.HandleFunc(majorVersionsPath, s.handleProductHeader)
+	mux.HandleFunc(dlgListPath, s.handleDlgList)
+	mux.HandleFunc(dlgHeaderPath, s.handleDlgHeader)
+	mux.HandleFunc(dlgDetailsPathPublic, s.handleDlgDetails(false))
+	mux.HandleFunc(dlgDetailsPathAuthenticated, s.handleDlgDetails(true))
+	mux.HandleFunc(eulaPath, s.handleEula)
+	mux.HandleFunc(downloadPath, s.handleDownload)
+	mux.HandleFunc(accountInfoPath, s.handleAccountInfo)
+	mux.HandleFunc(currentUserPath, s.handleCurrentUser)
+	mux.HandleFunc(filesPath, s.handleFile)
+	return mux
+}
+
+// AuthURL returns the credential submission endpoint, which is served from the same host
+func (s *Server) AuthURL() string {
+	return s.URL + authPath + "?Auth-AppID=WMVMWR"
+}
+
+// RequestCount returns how many requests have been received for a path
+func (s *Server) RequestCount(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// FailNext makes the next requests for a path fail with the given status codes, one per request.
+// http.StatusOK returns an empty body, as the login endpoint intermittently does.
+func (s *Server) FailNext(path string, statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = append(s.failures[path], statuses...)
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		status := 0
+		if failures := s.failures[r.URL.Path]; len(failures) > 0 {
+			status, s.failures[r.URL.Path] = failures[0], failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleInit(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: "login_init", Value: randomToken(), Path: "/"})
+	w.Write([]byte("<html><body>login</body></html>"))
+}
+
+func (s *Server) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<html><body>Invalid username or password</body></html>"))
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.findUser(r.PostFormValue("username"), r.PostFormValue("password"))
+	if user == nil {
+		http.Redirect(w, r, loginPagePath, http.StatusFound)
+		return
+	}
+
+	token := randomToken()
+	s.mu.Lock()
+	s.tokens[token] = user.Username
+	s.mu.Unlock()
+
+	fmt.Fprintf(w, samlPage, s.URL+ssoPath, html.EscapeString(token))
+}
+
+func (s *Server) handleSSO(w http.ResponseWriter, r *http.Request) {
+	// Plain GET is used as a connectivity check
+	if r.Method != http.MethodPost {
+		return
+	}
+
+	token := r.PostFormValue("SAMLResponse")
+	s.mu.Lock()
+	username, ok := s.tokens[token]
+	delete(s.tokens, token)
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, xsrf := randomToken(), randomToken()
+	s.mu.Lock()
+	s.sessions[sessionID] = &session{
+		user: s.userByName(username),
+		xsrf: xsrf,
+	}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: sessionID, Path: "/", HttpOnly: true, Expires: time.Now().Add(24 * time.Hour)})
+	http.SetCookie(w, &http.Cookie{Name: XsrfCookie, Value: xsrf, Path: "/", Expires: time.Now().Add(24 * time.Hour)})
+	w.Write([]byte("<html><body>logged in</body></html>"))
+}
+
+// ExpireSessions invalidates all sessions, as happens when a session times out server side
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]*session)
+}
+
+func (s *Server) handleProducts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.fixtures.Products)
+}
+
+func (s *Server) handleProductHeader(w http.ResponseWriter, r *http.Request) {
+	s.serveFixture(w, s.fixtures.ProductHeaders, r.URL.Query().Get("product"))
+}
+
+func (s *Server) handleDlgList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.serveFixture(w, s.fixtures.DlgLists, path.Join(q.Get("product"), q.Get("version"), q.Get("dlgType")))
+}
+
+func (s *Server) handleDlgHeader(w http.ResponseWriter, r *http.Request) {
+	s.serveFixture(w, s.fixtures.DlgHeaders, downloadGroupKey(r.URL.Query()))
+}
+
+func (s *Server) handleDlgDetails(authenticated bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var sess *session
+		if authenticated {
+			if sess = s.session(r); sess == nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		key := downloadGroupKey(r.URL.Query())
+		details, ok := s.fixtures.DlgDetails[key]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]interface{}{"downloadFiles": details.DownloadFiles}
+		if authenticated {
+			response["eligibilityResponse"] = map[string]bool{"eligibleToDownload": details.EligibleToDownload}
+			response["eulaResponse"] = map[string]interface{}{
+				"eulaAccepted": details.EulaAccepted || s.eulaAccepted(sess, key),
+				"eulaURL":      details.EulaURL,
+			}
+		}
+		writeJSON(w, response)
+	}
+}
+
+func (s *Server) handleEula(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if sess == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	key := downloadGroupKey(r.URL.Query())
+	if _, ok := s.fixtures.DlgDetails[key]; !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	if s.eulas[sess.user.Username] == nil {
+		s.eulas[sess.user.Username] = make(map[string]bool)
+	}
+	s.eulas[sess.user.Username][key] = true
+	s.mu.Unlock()
+	writeJSON(w, map[string]string{"status": "success"})
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if sess == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost || r.Header.Get("X-XSRF-TOKEN") != sess.xsrf {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var payload struct {
+		DownloadGroup string `json:"downloadGroup"`
+		ProductId     string `json:"productId"`
+		UUId          string `json:"uUId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	key := payload.DownloadGroup + "/" + payload.ProductId
+	details, ok := s.fixtures.DlgDetails[key]
+	file, fileOk := s.fixtures.Files[payload.UUId]
+	if !ok || !fileOk {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !details.EligibleToDownload || !(details.EulaAccepted || s.eulaAccepted(sess, key)) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	writeJSON(w, map[string]string{
+		"downloadURL": s.URL + filesPath + payload.UUId + "/" + file.FileName,
+		"fileName":    file.FileName,
+	})
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	uuid := strings.SplitN(strings.TrimPrefix(r.URL.Path, filesPath), "/", 2)[0]
+	file, ok := s.fixtures.Files[uuid]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	// ServeContent handles range requests, which allows downloads to be resumed
+	http.ServeContent(w, r, file.FileName, time.Time{}, strings.NewReader(file.Content))
+}
+
+func (s *Server) handleAccountInfo(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if sess == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, sess.user.AccountInfo)
+}
+
+func (s *Server) handleCurrentUser(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if sess == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, map[string]string{"firstname": sess.user.Firstname, "lastname": sess.user.Lastname})
+}
+
+func (s *Server) serveFixture(w http.ResponseWriter, fixtures map[string]json.RawMessage, key string) {
+	fixture, ok := fixtures[key]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, fixture)
+}
+
+func (s *Server) session(r *http.Request) *session {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[cookie.Value]
+}
+
+func (s *Server) eulaAccepted(sess *session, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eulas[sess.user.Username][key]
+}
+
+func (s *Server) findUser(username, password string) *User {
+	user := s.userByName(username)
+	if user == nil || user.Password != password {
+		return nil
+	}
+	return user
+}
+
+func (s *Server) userByName(username string) *User {
+	for i := range s.fixtures.Users {
+		if s.fixtures.Users[i].Username == username {
+			return &s.fixtures.Users[i]
+		}
+	}
+	return nil
+}
+
+func downloadGroupKey(q url.Values) string {
+	return q.Get("downloadGroup") + "/" + q.Get("productId")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
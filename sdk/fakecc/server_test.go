@@ -0,0 +1,132 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package fakecc_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/persistent-cookiejar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func newClient(t *testing.T, srv *fakecc.Server) *sdk.Client {
+	t.Helper()
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	opts := sdk.ClientOptions{Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL())}
+	client, err := sdk.LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+	return client
+}
+
+func TestLogin(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	assert.NotEmpty(t, client.XsrfToken)
+
+	currentUser, err := client.CurrentUser()
+	assert.Nil(t, err)
+	assert.Equal(t, "Test", currentUser.FirstName)
+
+	accountInfo, err := client.AccountInfo()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, accountInfo.AccountList)
+}
+
+func TestFailedLogin(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	opts := sdk.ClientOptions{Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL())}
+	_, err := sdk.LoginWithOptions(fakecc.Username, "wrong", jar, opts)
+	assert.ErrorIs(t, err, sdk.ErrorAuthenticationFailure)
+}
+
+func TestPublicCatalog(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	client := sdk.NewClient(sdk.ClientOptions{Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL())})
+
+	products, err := client.GetProductsMap()
+	require.Nil(t, err)
+	assert.Contains(t, products, "vmware_tools")
+	assert.Contains(t, products, "vmware_vsphere")
+
+	subProducts, err := client.GetSubProductsMap("vmware_vsphere", "PRODUCT_BINARY", "")
+	require.Nil(t, err)
+	assert.Contains(t, subProducts, "esxi")
+
+	versions, err := client.GetVersionMap("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Contains(t, versions, "11.1.1")
+	assert.Equal(t, "VMTOOLS1111", versions["11.1.1"].Code)
+
+	dlgDetails, err := client.GetDlgDetails("VMTOOLS1111", "1073")
+	require.Nil(t, err)
+	assert.NotEmpty(t, dlgDetails.DownloadDetails)
+
+	_, err = client.GetDlgDetails("VMTOOLS666", "1073")
+	assert.ErrorIs(t, err, sdk.ErrorDlgDetailsInputs)
+
+	_, err = client.AccountInfo()
+	assert.ErrorIs(t, err, sdk.ErrorNotAuthorized)
+}
+
+func TestDownload(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	client := newClient(t, srv)
+
+	_, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.1.0", "VMware-Tools-darwin-*.tar.gz", "PRODUCT_BINARY", false)
+	assert.ErrorIs(t, err, sdk.ErrorEulaUnaccepted)
+
+	_, err = client.GenerateDownloadPayload("vmware_vsphere", "esxi", "7.0U3", "*.iso", "PRODUCT_BINARY", true)
+	assert.ErrorIs(t, err, sdk.ErrorNotEntitled)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.1.0", "VMware-Tools-darwin-*.tar.gz", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	require.Len(t, payloads, 1)
+
+	authorizedDownload, err := client.FetchDownloadLink(payloads[0])
+	require.Nil(t, err)
+	assert.Equal(t, "VMware-Tools-darwin-11.1.0-16036546.tar.gz", authorizedDownload.FileName)
+
+	res, err := http.Get(authorizedDownload.DownloadURL)
+	require.Nil(t, err)
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Contains(t, string(body), authorizedDownload.FileName)
+}
+
+func TestExpireSessions(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	assert.Nil(t, client.CheckLoggedIn())
+
+	logins := srv.RequestCount("/vmwauth/saml/SSO")
+	srv.ExpireSessions()
+
+	res, err := client.HttpClient.Post(srv.URL+"/channel/api/v1.0/ems/accountinfo", "application/json", strings.NewReader("{}"))
+	require.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// The client logs in again with the credentials it was created with
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, logins+1, srv.RequestCount("/vmwauth/saml/SSO"))
+}
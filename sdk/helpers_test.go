@@ -4,13 +4,76 @@
 package sdk
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"testing"
+
+	"github.com/orirawlings/persistent-cookiejar"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
 )
 
 var err error
 
+// testHelperEnv makes the test binary act as the named helper process instead of running the tests,
+// so tests of external commands do not depend on a shell
+const testHelperEnv = "VCC_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(testHelperEnv); name != "" {
+		os.Exit(runTestHelper(name))
+	}
+	os.Exit(m.Run())
+}
+
+// helperCommand returns the path of the test binary, which runs the named helper when executed
+func helperCommand(t *testing.T, name string) string {
+	t.Helper()
+
+	t.Setenv(testHelperEnv, name)
+	return os.Args[0]
+}
+
+func runTestHelper(name string) int {
+	switch name {
+	case "credentials":
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if line != "protocol=https\n" {
+			return 1
+		}
+		fmt.Println("username=user@example.com")
+		fmt.Println("password=secret")
+	case "username-only":
+		fmt.Println("username=user@example.com")
+	case "fail":
+		return 1
+	case "security-not-found":
+		return securityItemNotFound
+	case "security-locked":
+		fmt.Fprintln(os.Stderr, "User interaction is not allowed.")
+		return 36
+	case "security-store":
+		// The command, including the secret, must only be passed on stdin to the interactive mode
+		if len(os.Args) != 2 || os.Args[1] != "-i" {
+			return 1
+		}
+		command := `add-generic-password -U -s "` + DefaultKeyringService + `" -a "user@example.com" -w "se\"c\\ret"` + "\n"
+		if data, _ := io.ReadAll(os.Stdin); string(data) != command {
+			return 1
+		}
+	case "secret-tool-no-dbus":
+		fmt.Fprintln(os.Stderr, "Cannot autolaunch D-Bus without X11 $DISPLAY")
+		return 1
+	default:
+		return 2
+	}
+	return 0
+}
+
 // Print contents of object
 // b, _ := json.Marshal(data)
 // fmt.Println(string(b))
@@ -20,10 +83,55 @@ var basicClient = Client{HttpClient: &http.Client{}}
 func mustEnv(t *testing.T, k string) string {
 	t.Helper()
 
-	if v, ok := os.LookupEnv(k); ok {
+	// CI of forks sets the variables to empty values, as secrets are not available to them
+	if v := os.Getenv(k); v != "" {
 		return v
 	}
 
-	t.Fatalf("expected environment variable %q", k)
+	t.Skipf("environment variable %q is not set, skipping test against the live service", k)
 	return ""
 }
+
+// skipUnlessLive skips tests which need the live Customer Connect service.
+// They only run when credentials are provided, e.g. in CI of the main repository.
+func skipUnlessLive(t *testing.T) {
+	t.Helper()
+
+	mustEnv(t, "VMWCC_USER")
+	mustEnv(t, "VMWCC_PASS")
+}
+
+// newFakeServer starts a fake Customer Connect server, so tests can run without credentials
+func newFakeServer(t *testing.T) *fakecc.Server {
+	t.Helper()
+
+	srv := fakecc.NewServer(nil)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newFakeClient returns an unauthenticated client of a new fake server
+func newFakeClient(t *testing.T) *Client {
+	t.Helper()
+
+	return NewClient(fakeClientOptions(newFakeServer(t)))
+}
+
+func fakeClientOptions(srv *fakecc.Server) ClientOptions {
+	return ClientOptions{Endpoints: NewEndpoints(srv.URL, srv.AuthURL())}
+}
+
+func fakeLogin(t *testing.T, srv *fakecc.Server) *Client {
+	t.Helper()
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, fakeClientOptions(srv))
+	require.Nil(t, err)
+	return client
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const DefaultKeyringService = "vmware-customer-connect"
+
+var (
+	ErrorSecretNotFound     = errors.New("keyring: secret not found")
+	ErrorKeyringUnavailable = errors.New("keyring: no system keyring available")
+)
+
+// Keyring stores secrets by service and account name
+type Keyring interface {
+	Get(service, account string) (secret string, err error)
+	Set(service, account, secret string) error
+}
+
+// KeyringCredentials reads the password of Username from a keyring
+type KeyringCredentials struct {
+	Keyring  Keyring
+	Username string
+	// Defaults to DefaultKeyringService
+	Service string
+}
+
+func (k KeyringCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	service := k.Service
+	if service == "" {
+		service = DefaultKeyringService
+	}
+
+	if password, err = k.Keyring.Get(service, k.Username); err != nil {
+		if errors.Is(err, ErrorSecretNotFound) {
+			err = fmt.Errorf("%w: %v", ErrorMissingCredentials, err)
+		}
+		return
+	}
+	username = k.Username
+	return
+}
+
+// NewKeyring returns the keyring of the operating system when one is available,
+// otherwise secrets are stored in a FileKeyring at fallbackPath.
+func NewKeyring(fallbackPath string) Keyring {
+	if keyring, err := SystemKeyring(); err == nil {
+		return keyring
+	}
+	return &FileKeyring{Path: fallbackPath}
+}
+
+// SystemKeyring uses the macOS keychain through the security command,
+// or the Secret Service on Linux through secret-tool.
+func SystemKeyring() (keyring Keyring, err error) {
+	var command string
+	switch runtime.GOOS {
+	case "darwin":
+		command = "security"
+	case "linux", "freebsd", "openbsd":
+		command = "secret-tool"
+	default:
+		err = ErrorKeyringUnavailable
+		return
+	}
+
+	var path string
+	if path, err = exec.LookPath(command); err != nil {
+		err = fmt.Errorf("%w: %v", ErrorKeyringUnavailable, err)
+		return
+	}
+	keyring = commandKeyring{command: command, path: path}
+	return
+}
+
+// securityItemNotFound is the exit status of security for errSecItemNotFound
+const securityItemNotFound = 44
+
+type commandKeyring struct {
+	// security or secret-tool
+	command string
+	path    string
+}
+
+func (k commandKeyring) Get(service, account string) (secret string, err error) {
+	var cmd *exec.Cmd
+	if k.command == "security" {
+		cmd = exec.Command(k.path, "find-generic-password", "-s", service, "-a", account, "-w")
+	} else {
+		cmd = exec.Command(k.path, "lookup", "service", service, "account", account)
+	}
+
+	var out []byte
+	out, err = cmd.Output()
+	secret = strings.TrimSuffix(string(out), "\n")
+
+	// Only the documented not found status means there is no secret. Other failures, e.g. a
+	// locked keychain or no D-Bus session, are returned as they are.
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && k.notFound(exitErr, secret):
+		err = fmt.Errorf("%w: %s/%s", ErrorSecretNotFound, service, account)
+	case exitErr != nil:
+		err = fmt.Errorf("keyring: %s: %w: %s", k.command, err, strings.TrimSpace(string(exitErr.Stderr)))
+	case err == nil && secret == "":
+		err = fmt.Errorf("%w: %s/%s", ErrorSecretNotFound, service, account)
+	}
+	return
+}
+
+// security exits with 44 when the item does not exist, secret-tool with 1 and no output at all
+func (k commandKeyring) notFound(exitErr *exec.ExitError, stdout string) bool {
+	if k.command == "security" {
+		return exitErr.ExitCode() == securityItemNotFound
+	}
+	return exitErr.ExitCode() == 1 && stdout == "" && len(exitErr.Stderr) == 0
+}
+
+func (k commandKeyring) Set(service, account, secret string) (err error) {
+	// The secret is written to stdin, so it does not show up in the process list
+	var cmd *exec.Cmd
+	if k.command == "security" {
+		// security prompts for a missing -w value on the terminal, so the whole command is
+		// passed to its interactive mode instead, which reads commands from stdin
+		if strings.ContainsAny(service+account+secret, "\r\n") {
+			err = fmt.Errorf("keyring: %s: line breaks are not supported", k.command)
+			return
+		}
+		cmd = exec.Command(k.path, "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			securityQuote(service), securityQuote(account), securityQuote(secret)))
+	} else {
+		cmd = exec.Command(k.path, "store", "--label", service+" "+account, "service", service, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+
+	if out, runErr := cmd.CombinedOutput(); runErr != nil {
+		err = fmt.Errorf("keyring: %s: %w: %s", k.command, runErr, strings.TrimSpace(string(out)))
+	}
+	return
+}
+
+var securityQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// securityQuote quotes an argument for the interactive mode of security
+func securityQuote(arg string) string {
+	return `"` + securityQuoter.Replace(arg) + `"`
+}
+
+// FileKeyring stores secrets in a JSON file readable only by the current user.
+// Secrets are not encrypted, so it is intended for hosts without a system keyring, e.g. CI runners.
+type FileKeyring struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (f *FileKeyring) Get(service, account string) (secret string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var secrets map[string]map[string]string
+	if secrets, err = f.load(); err != nil {
+		return
+	}
+
+	secret, ok := secrets[service][account]
+	if !ok {
+		err = fmt.Errorf("%w: %s/%s", ErrorSecretNotFound, service, account)
+	}
+	return
+}
+
+func (f *FileKeyring) Set(service, account, secret string) (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var secrets map[string]map[string]string
+	if secrets, err = f.load(); err != nil {
+		return
+	}
+	if secrets[service] == nil {
+		secrets[service] = make(map[string]string)
+	}
+	secrets[service][account] = secret
+
+	var data []byte
+	if data, err = json.MarshalIndent(secrets, "", "  "); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return
+	}
+	return os.WriteFile(f.Path, data, 0600)
+}
+
+func (f *FileKeyring) load() (secrets map[string]map[string]string, err error) {
+	secrets = make(map[string]map[string]string)
+
+	var data []byte
+	data, err = os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	} else if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &secrets)
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vcc", "keyring.json")
+	keyring := &FileKeyring{Path: path}
+
+	_, err := keyring.Get(DefaultKeyringService, "user@example.com")
+	assert.ErrorIs(t, err, ErrorSecretNotFound)
+
+	require.Nil(t, keyring.Set(DefaultKeyringService, "user@example.com", "secret"))
+	require.Nil(t, keyring.Set(DefaultKeyringService, "other@example.com", "other-secret"))
+
+	// Secrets are read back from the file
+	secret, err := (&FileKeyring{Path: path}).Get(DefaultKeyringService, "user@example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "secret", secret)
+
+	// Windows does not have Unix permission bits
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestNewKeyringFallback(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	keyring := NewKeyring(filepath.Join(t.TempDir(), "keyring.json"))
+	assert.IsType(t, &FileKeyring{}, keyring)
+}
+
+func TestCommandKeyring(t *testing.T) {
+	security := commandKeyring{command: "security", path: helperCommand(t, "security-not-found")}
+	_, err := security.Get(DefaultKeyringService, "user@example.com")
+	assert.ErrorIs(t, err, ErrorSecretNotFound)
+
+	security.path = helperCommand(t, "security-locked")
+	_, err = security.Get(DefaultKeyringService, "user@example.com")
+	require.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrorSecretNotFound)
+	assert.Contains(t, err.Error(), "User interaction is not allowed")
+
+	security.path = helperCommand(t, "security-store")
+	assert.Nil(t, security.Set(DefaultKeyringService, "user@example.com", `se"c\ret`))
+	assert.NotNil(t, security.Set(DefaultKeyringService, "user@example.com", "se\ncret"))
+
+	secretTool := commandKeyring{command: "secret-tool", path: helperCommand(t, "fail")}
+	_, err = secretTool.Get(DefaultKeyringService, "user@example.com")
+	assert.ErrorIs(t, err, ErrorSecretNotFound)
+
+	secretTool.path = helperCommand(t, "secret-tool-no-dbus")
+	_, err = secretTool.Get(DefaultKeyringService, "user@example.com")
+	require.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrorSecretNotFound)
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// All SDK logs are written at debug level. Headers and bodies are never logged, so credentials and
+// cookies cannot leak, and URLs are redacted with the same rules as APIError.
+
+var discardLogger = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+func loggerOrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return discardLogger
+	}
+	return logger
+}
+
+func (c *Client) log() *slog.Logger {
+	return loggerOrDiscard(c.logger)
+}
+
+// loggingTransport logs every request sent, including each retry
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	start := time.Now()
+	res, err = t.next.RoundTrip(req)
+
+	attrs := []any{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+		"duration", time.Since(start),
+	}
+	if err != nil {
+		t.logger.DebugContext(req.Context(), "http request failed", append(attrs, "error", err)...)
+		return
+	}
+	t.logger.DebugContext(req.Context(), "http request", append(attrs, "status", res.StatusCode)...)
+	return
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func TestLogging(t *testing.T) {
+	srv := newFakeServer(t)
+	var logs bytes.Buffer
+	opts := fakeClientOptions(srv)
+	opts.Logger = slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	jar, _ := cookiejar.New(nil)
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "12.3.0", "VMware-Tools-windows-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+	_, err = client.FetchDownloadLink(payloads[0])
+	require.Nil(t, err)
+
+	output := logs.String()
+	for _, stage := range []string{"init", "auth submit", "saml", "sso"} {
+		assert.Contains(t, output, `"msg":"login stage","stage":"`+stage+`"`)
+	}
+	assert.Contains(t, output, `"msg":"http request","method":"POST","url":"`+srv.URL+downloadPath+`"`)
+	assert.Contains(t, output, `"msg":"normalized sub-product"`)
+	assert.Contains(t, output, `"status":200`)
+
+	// Credentials and cookies are never logged
+	assert.NotContains(t, output, fakecc.Password)
+	u, _ := url.Parse(srv.URL)
+	for _, cookie := range jar.Cookies(u) {
+		assert.NotContains(t, output, cookie.Value)
+	}
+	assert.NotContains(t, output, client.CurrentXsrfToken())
+}
+
+func TestLoggingRetries(t *testing.T) {
+	srv := newFakeServer(t)
+	var logs bytes.Buffer
+	opts := fakeClientOptions(srv)
+	opts.RetryPolicy = fastRetryPolicy(2)
+	opts.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	srv.FailNext(productsTestPath, 503)
+	_, err := NewClient(opts).GetProductsSlice()
+	require.Nil(t, err)
+	assert.Contains(t, logs.String(), `msg="retrying request" method=GET url="`+srv.URL+productsPath+`" attempt=1 status=503`)
+}
@@ -5,21 +5,50 @@ package sdk
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/andybalholm/cascadia"
-	"github.com/orirawlings/persistent-cookiejar"
 	"golang.org/x/net/html"
 )
 
+// Client is safe for concurrent use by multiple goroutines. The product catalog and session state
+// are guarded internally, so a single Client should be shared rather than one created per goroutine.
+// The exported fields must not be modified once the Client is in use.
 type Client struct {
 	HttpClient *http.Client
-	XsrfToken  string
+	// XsrfToken is set at login. Once the client is shared use CurrentXsrfToken to read it.
+	XsrfToken string
+	Endpoints Endpoints
+
+	// Guards XsrfToken and the session generation, which is incremented on every re-login
+	sessionMu         sync.RWMutex
+	sessionGeneration uint64
+	jar               http.CookieJar
+	credentials       CredentialProvider
+	onSessionRefresh  func(jar http.CookieJar)
+	sessionStore      SessionStore
+	// Serializes re-logins, so readers of the session are not blocked while logging in
+	refreshMu sync.Mutex
+
+	cache    Cache
+	cacheTTL time.Duration
+	logger   *slog.Logger
+	tracer   Tracer
+	snapshot *CatalogSnapshot
+
+	// Product catalog keyed by slug, see EnsureProductDetailMap
+	catalogMu sync.Mutex
+	catalog   map[string]ProductDetails
 }
 
 type TokenValidation struct {
@@ -33,10 +62,8 @@ type TokenValidation struct {
 }
 
 const (
-	baseURL = "https://customerconnect.vmware.com"
-	initURL = baseURL + "/web/vmware/login"
-	ssoURL  = baseURL + "/vmwauth/saml/SSO"
-	authURL = "https://auth.vmware.com/oam/server/auth_cred_submit?Auth-AppID=WMVMWR"
+	initPath = "/web/vmware/login"
+	ssoPath  = "/vmwauth/saml/SSO"
 )
 
 const samlInputQuery = `input[name="SAMLResponse"]`
@@ -48,23 +75,53 @@ var ErrorAuthenticationFailure = errors.New("login: authentication failure")
 var ErrorXsrfFailure = errors.New("login: server did not return XSRF token")
 var ErrorConnectionFailure = errors.New("login: server did not return 200 ok")
 
-func Login(username, password string, jar *cookiejar.Jar) (client *Client, err error) {
-	err = CheckConnectivity()
+// Login authenticates with Customer Connect. jar can be any http.CookieJar, e.g. from net/http/cookiejar
+// when the session does not need to be kept, or nil to use an in-memory jar.
+func Login(username, password string, jar http.CookieJar) (client *Client, err error) {
+	return LoginWithOptions(username, password, jar, ClientOptions{})
+}
+
+// LoginWithOptions behaves as Login, but allows the endpoints to be overridden
+func LoginWithOptions(username, password string, jar http.CookieJar, opts ClientOptions) (client *Client, err error) {
+	return LoginCtx(context.Background(), username, password, jar, opts)
+}
+
+// LoginCtx behaves as LoginWithOptions, aborting the login when ctx is cancelled
+func LoginCtx(ctx context.Context, username, password string, jar http.CookieJar, opts ClientOptions) (client *Client, err error) {
+	endpoints := opts.Endpoints.withDefaults()
+	logger := loggerOrDiscard(opts.Logger)
+
+	err = checkConnectivity(ctx, newHTTPClient(nil, opts), endpoints)
 	if err != nil {
 		return
 	}
 
-	httpClient := &http.Client{Jar: jar}
+	if jar == nil {
+		jar, _ = cookiejar.New(nil)
+	}
+	httpClient := newHTTPClient(jar, opts)
+
+	// A stored session is only a cache. When it cannot be read, e.g. it is corrupt or was encrypted
+	// with another passphrase, log in again and let saveSession replace it.
+	if opts.SessionStore != nil {
+		session, loadErr := opts.SessionStore.Load(ctx)
+		if loadErr == nil {
+			loadErr = restoreSession(jar, endpoints, session)
+		}
+		if loadErr != nil && !errors.Is(loadErr, ErrorNoSession) {
+			logger.DebugContext(ctx, "ignoring stored session", "error", loadErr)
+		}
+	}
 
 	// When cookies are passed in and check to see can make calls
 	// Otherwise perform a login
-	_, errXsrf := setXsrfToken(httpClient)
+	_, errXsrf := setXsrfToken(httpClient, endpoints)
 	loginNeeded := false
-	if len(jar.AllCookies()) > 0 && errXsrf == nil {
+	if hasCookies(jar, endpoints) && errXsrf == nil {
 
 		payload := `{"rowLimit": 10}`
 		var res *http.Response
-		res, err = httpClient.Post(accountInfoURL, "application/json", strings.NewReader(payload))
+		res, err = httpPost(ctx, httpClient, endpoints.AccountInfo, "application/json", strings.NewReader(payload))
 		if err != nil {
 			return
 		}
@@ -72,35 +129,130 @@ func Login(username, password string, jar *cookiejar.Jar) (client *Client, err e
 
 		if res.StatusCode == 401 || res.StatusCode == 500 {
 			loginNeeded = true
+			logger.DebugContext(ctx, "existing session is not valid", "status", res.StatusCode)
+		} else {
+			logger.DebugContext(ctx, "reusing existing session")
 		}
 	} else {
 		loginNeeded = true
 	}
 
 	if loginNeeded {
-		jar.RemoveAll()
-		err = performLogin(httpClient, username, password, jar)
+		clearCookies(jar, endpoints)
+		err = performLogin(ctx, httpClient, endpoints, username, password, logger)
 		if err != nil {
 			return
 		}
+		if err = saveSession(ctx, opts.SessionStore, jar, endpoints); err != nil {
+			return
+		}
 	}
 
 	var xsrfToken string
-	if xsrfToken, err = setXsrfToken(httpClient); err != nil {
+	if xsrfToken, err = setXsrfToken(httpClient, endpoints); err != nil {
 		return
 	}
 
-	client = &Client{
-		HttpClient: httpClient,
-		XsrfToken:  xsrfToken,
+	client = newClient(httpClient, opts)
+	client.XsrfToken = xsrfToken
+	client.jar = jar
+	if client.credentials == nil {
+		client.credentials = StaticCredentials{Username: username, Password: password}
 	}
 
 	return
 }
 
+// LoginWithProvider logs in with credentials from provider, e.g. EnvCredentials or KeyringCredentials.
+// The provider is also used to log in again when the session expires.
+func LoginWithProvider(ctx context.Context, provider CredentialProvider, jar http.CookieJar, opts ClientOptions) (client *Client, err error) {
+	var username, password string
+	if username, password, err = provider.Credentials(ctx); err != nil {
+		return
+	}
+	opts.Credentials = provider
+	return LoginCtx(ctx, username, password, jar, opts)
+}
+
+// CurrentXsrfToken returns the XSRF token of the current session
+func (c *Client) CurrentXsrfToken() string {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.XsrfToken
+}
+
+func (c *Client) currentSession() (xsrfToken string, generation uint64) {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.XsrfToken, c.sessionGeneration
+}
+
+// reauthenticate logs in again after the session expired. generation is the session the failed
+// request was made with, so when several requests fail at once only the first one logs in.
+// sessionMu is only held to swap in the new session, not during the login itself.
+func (c *Client) reauthenticate(ctx context.Context, generation uint64) (err error) {
+	c.refreshMu.Lock()
+	if _, current := c.currentSession(); current != generation {
+		c.refreshMu.Unlock()
+		return
+	}
+
+	refreshed := false
+	defer func() {
+		c.refreshMu.Unlock()
+		if refreshed && c.onSessionRefresh != nil {
+			c.onSessionRefresh(c.jar)
+		}
+	}()
+
+	var username, password string
+	if username, password, err = c.credentials.Credentials(ctx); err != nil {
+		return
+	}
+
+	c.log().DebugContext(ctx, "session expired, logging in again", "generation", generation)
+	endpoints := c.endpoints()
+	clearCookies(c.jar, endpoints)
+	if err = performLogin(ctx, c.HttpClient, endpoints, username, password, c.log()); err != nil {
+		return
+	}
+
+	var xsrfToken string
+	if xsrfToken, err = setXsrfToken(c.HttpClient, endpoints); err != nil {
+		return
+	}
+	c.sessionMu.Lock()
+	c.XsrfToken = xsrfToken
+	c.sessionGeneration++
+	c.sessionMu.Unlock()
+	refreshed = true
+
+	// The new session is usable even when it cannot be stored, so the request is still retried
+	if saveErr := saveSession(ctx, c.sessionStore, c.jar, endpoints); saveErr != nil {
+		c.log().DebugContext(ctx, "failed to store the refreshed session", "error", saveErr)
+	}
+
+	return
+}
+
+func saveSession(ctx context.Context, store SessionStore, jar http.CookieJar, endpoints Endpoints) (err error) {
+	if store == nil {
+		return
+	}
+
+	var session *Session
+	if session, err = sessionFromJar(jar, endpoints); err != nil {
+		return
+	}
+	return store.Save(ctx, session)
+}
+
 // Extract xsrf token value to be used when getting download link
-func setXsrfToken(client *http.Client) (xsrfToken string, err error) {
-	u, _ := url.Parse("https://customerconnect.vmware.com")
+func setXsrfToken(client *http.Client, endpoints Endpoints) (xsrfToken string, err error) {
+	var u *url.URL
+	if u, err = url.Parse(endpoints.BaseURL); err != nil {
+		return
+	}
 	cookies := client.Jar.Cookies(u)
 	for _, cookie := range cookies {
 		if cookie.Name == "XSRF-TOKEN" {
@@ -114,66 +266,56 @@ func setXsrfToken(client *http.Client) (xsrfToken string, err error) {
 	return
 }
 
-func performLogin(httpClient *http.Client, username, password string, jar *cookiejar.Jar) (err error) {
+// The auth endpoint intermittently returns an empty body. The session it belongs to cannot be used,
+// so the cookies are cleared and the login starts again from the init page.
+const loginAttempts = 4
+
+func performLogin(ctx context.Context, httpClient *http.Client, endpoints Endpoints, username, password string, logger *slog.Logger) (err error) {
+	logStage := func(stage string) {
+		logger.DebugContext(ctx, "login stage", "stage", stage)
+	}
 
 	var buf bytes.Buffer
-	
-	// Attempting login 5 times, to allow for empty response issue.
-	for i := 1; i < 5; i++ {
-		// Initialize cookies
-		var initRes *http.Response
-		initRes, err = httpClient.Get(initURL)
-		if err != nil {
-			break
-		}
-		initRes.Body.Close()
-		
-		// Error if connaction cannot be made to login endpoint
-		if initRes.StatusCode != 200 {
-			err = ErrorConnectionFailure
-			break
-		}
-		
-		// Post credentials to get SAML token back
+	for attempt := 1; ; attempt++ {
 		var authResp *http.Response
-		authResp, err = httpClient.PostForm(authURL, url.Values{
-			"username": {username},
-			"password": {password},
-		})
+		authResp, err = submitCredentials(ctx, httpClient, endpoints, username, password, logStage)
 		if err != nil {
-			break
+			return
 		}
-		defer authResp.Body.Close()
-		
-		tee := io.TeeReader(authResp.Body, &buf)
-		
-		var authBodyBytes []byte
-		authBodyBytes, err = io.ReadAll(tee)
-		str_body := string(authBodyBytes)
-		
-		if str_body == "" {
-			jar.RemoveAll()
-			continue
+
+		buf.Reset()
+		_, err = io.Copy(&buf, authResp.Body)
+		authResp.Body.Close()
+		if err != nil {
+			return
 		}
 
 		// Return auth failure if reposonse is redirect to the login page
 		if authResp.Request.URL.Path == "/login" {
 			err = ErrorAuthenticationFailure
+			return
 		}
-		break
-	}
-	
-	if err != nil {
-		return
+		if buf.Len() > 0 {
+			break
+		}
+		if attempt >= loginAttempts {
+			err = newAPIError(authResp, ErrorConnectionFailure)
+			return
+		}
+
+		logger.DebugContext(ctx, "empty login response, starting again", "attempt", attempt)
+		clearCookies(httpClient.Jar, endpoints)
 	}
 
+	logStage("saml")
 	samlToken, err := getSAMLToken(&buf)
 	if err != nil {
 		return
 	}
 
 	// Post SAML token to generate final session cookies
-	ssoRes, err := httpClient.PostForm(ssoURL, url.Values{
+	logStage("sso")
+	ssoRes, err := httpPostForm(ctx, httpClient, endpoints.SSO, url.Values{
 		"SAMLResponse": {samlToken},
 	})
 	if err != nil {
@@ -184,6 +326,29 @@ func performLogin(httpClient *http.Client, username, password string, jar *cooki
 	return
 }
 
+// Initialize the login cookies and post the credentials to get a SAML token back
+func submitCredentials(ctx context.Context, httpClient *http.Client, endpoints Endpoints, username, password string, logStage func(stage string)) (authResp *http.Response, err error) {
+	logStage("init")
+	var initRes *http.Response
+	initRes, err = httpGet(ctx, httpClient, endpoints.Init)
+	if err != nil {
+		return
+	}
+	defer initRes.Body.Close()
+
+	// Error if connaction cannot be made to login endpoint
+	if initRes.StatusCode != 200 {
+		err = newAPIError(initRes, ErrorConnectionFailure)
+		return
+	}
+
+	logStage("auth submit")
+	return httpPostForm(ctx, httpClient, endpoints.AuthURL, url.Values{
+		"username": {username},
+		"password": {password},
+	})
+}
+
 // Extract SAML token from HTML body
 func getSAMLToken(body io.Reader) (string, error) {
 	doc, err := html.Parse(body)
@@ -206,12 +371,23 @@ func getSAMLToken(body io.Reader) (string, error) {
 }
 
 func CheckConnectivity() (err error) {
-	httpClient := &http.Client{}
+	return CheckConnectivityCtx(context.Background(), ClientOptions{})
+}
+
+func CheckConnectivityCtx(ctx context.Context, opts ClientOptions) (err error) {
+	return checkConnectivity(ctx, newHTTPClient(nil, opts), opts.Endpoints.withDefaults())
+}
+
+func checkConnectivity(ctx context.Context, httpClient *http.Client, endpoints Endpoints) (err error) {
 	var res *http.Response
-	res, err = httpClient.Get(ssoURL)
+	res, err = httpGet(ctx, httpClient, endpoints.SSO)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		err = ErrorConnectionFailure
+		err = newAPIError(res, ErrorConnectionFailure)
 	}
 
 	return
@@ -4,43 +4,38 @@
 package sdk
 
 import (
+	"context"
+	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/orirawlings/persistent-cookiejar"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
 )
 
 var authenticatedClient *Client
 
 func ensureLogin(t *testing.T) (err error) {
 	if authenticatedClient == nil {
-		var jar *cookiejar.Jar
-		// Persist cookies on file system to speed up testing
-		jar, err = cookiejar.New(&cookiejar.Options{
-			Filename:              filepath.Join(homeDir(), ".vmware.cookies"),
-			PersistSessionCookies: true,
-		})
-		if err != nil {return}
 		user, pass := mustEnv(t, "VMWCC_USER"), mustEnv(t, "VMWCC_PASS")
-		authenticatedClient, err = Login(user, pass, jar)
-		if err == nil {
-			err = jar.Save()
+		// Persist the session encrypted when a key is set, to speed up repeated test runs.
+		// The file is kept apart from the session of vcc, which may use another key.
+		var opts ClientOptions
+		if os.Getenv(DefaultSessionKeyEnv) != "" {
+			opts.SessionStore = EncryptedFileSessionStore{Path: filepath.Join(os.TempDir(), "vmwcc-sdk-test.session")}
 		}
+		authenticatedClient, err = LoginWithOptions(user, pass, nil, opts)
 	}
 	return
 }
 
-// homeDir returns the OS-specific home path as specified in the environment.
-func homeDir() string {
-	if runtime.GOOS == "windows" {
-		return filepath.Join(os.Getenv("HOMEDRIVE"), os.Getenv("HOMEPATH"))
-	}
-	return os.Getenv("HOME")
-}
-
 func TestSuccessfulLogin(t *testing.T) {
 	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
 	user, pass := mustEnv(t, "VMWCC_USER"), mustEnv(t, "VMWCC_PASS")
@@ -50,13 +45,162 @@ func TestSuccessfulLogin(t *testing.T) {
 
 func TestFailedLogin(t *testing.T) {
 	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
-	_, err = Login("user", "pass", jar)
+	_, err = LoginWithOptions("user", "pass", jar, fakeClientOptions(newFakeServer(t)))
 	assert.ErrorIs(t, err, ErrorAuthenticationFailure)
 }
 
 func TestSuccessfulConnection(t *testing.T) {
+	skipUnlessLive(t)
+
 	err = CheckConnectivity()
 	if err != nil {
 		t.Errorf("Expected error not to occur, got %q", err)
 	}
 }
+
+func TestReauthenticateExpiredSession(t *testing.T) {
+	srv := newFakeServer(t)
+	var refreshes int32
+	opts := fakeClientOptions(srv)
+	opts.OnSessionRefresh = func(jar http.CookieJar) {
+		atomic.AddInt32(&refreshes, 1)
+	}
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+	oldToken := client.CurrentXsrfToken()
+
+	payloads, err := client.GenerateDownloadPayload("vmware_tools", "vmtools", "11.3.5", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	require.Nil(t, err)
+
+	srv.ExpireSessions()
+
+	// Download links need the XSRF token of the new session
+	authorizedDownload, err := client.FetchDownloadLink(payloads[0])
+	require.Nil(t, err)
+	assert.NotEmpty(t, authorizedDownload.DownloadURL)
+	assert.NotEqual(t, oldToken, client.CurrentXsrfToken())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+}
+
+func TestReauthenticateOnce(t *testing.T) {
+	srv := newFakeServer(t)
+	var refreshes int32
+	opts := fakeClientOptions(srv)
+	opts.OnSessionRefresh = func(jar http.CookieJar) {
+		atomic.AddInt32(&refreshes, 1)
+	}
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+
+	srv.ExpireSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, client.CheckLoggedIn())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+}
+
+// unsavableSessionStore fails to save sessions once failSave is set
+type unsavableSessionStore struct {
+	MemorySessionStore
+	failSave atomic.Bool
+}
+
+func (s *unsavableSessionStore) Save(ctx context.Context, session *Session) error {
+	if s.failSave.Load() {
+		return os.ErrPermission
+	}
+	return s.MemorySessionStore.Save(ctx, session)
+}
+
+func TestReauthenticateSaveFailure(t *testing.T) {
+	srv := newFakeServer(t)
+	store := &unsavableSessionStore{}
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = store
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+
+	store.failSave.Store(true)
+	srv.ExpireSessions()
+
+	// The request is retried with the new session, although it could not be stored
+	_, err = client.AccountInfo()
+	assert.Nil(t, err)
+}
+
+// blockingCredentials waits for release before returning, signalling started when called
+type blockingCredentials struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b blockingCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	b.started <- struct{}{}
+	<-b.release
+	return fakecc.Username, fakecc.Password, nil
+}
+
+func TestReauthenticateDoesNotBlockReaders(t *testing.T) {
+	srv := newFakeServer(t)
+	credentials := blockingCredentials{started: make(chan struct{}, 1), release: make(chan struct{})}
+	opts := fakeClientOptions(srv)
+	opts.Credentials = credentials
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+	oldToken := client.CurrentXsrfToken()
+
+	srv.ExpireSessions()
+	loggedIn := make(chan error)
+	go func() { loggedIn <- client.CheckLoggedIn() }()
+	<-credentials.started
+
+	// The session can be read while the credentials are fetched
+	read := make(chan string)
+	go func() { read <- client.CurrentXsrfToken() }()
+	select {
+	case token := <-read:
+		assert.Equal(t, oldToken, token)
+	case <-time.After(5 * time.Second):
+		t.Fatal("CurrentXsrfToken blocked during re-login")
+	}
+
+	close(credentials.release)
+	assert.Nil(t, <-loggedIn)
+	assert.NotEqual(t, oldToken, client.CurrentXsrfToken())
+}
+
+type failingCredentials struct{}
+
+func (failingCredentials) Credentials(ctx context.Context) (username, password string, err error) {
+	return fakecc.Username, "wrong", nil
+}
+
+func TestReauthenticateFailure(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.Credentials = failingCredentials{}
+
+	jar, _ := cookiejar.New(&cookiejar.Options{NoPersist: true})
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+
+	srv.ExpireSessions()
+
+	_, err = client.AccountInfo()
+	assert.ErrorIs(t, err, ErrorAuthenticationFailure)
+}
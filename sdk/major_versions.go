@@ -4,13 +4,14 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 )
 
 const (
-	majorVersionsURL = baseURL + "/channel/public/api/v1.0/products/getProductHeader"
+	majorVersionsPath = "/channel/public/api/v1.0/products/getProductHeader"
 )
 
 type ProductVersions struct {
@@ -25,20 +26,21 @@ var ErrorInvalidMajorVersion = errors.New("major version not found")
 
 // curl "https://customerconnect.vmware.com/channel/public/api/v1.0/products/getProductHeader?category=datacenter_cloud_infrastructure&product=vmware_vsphere_storage_appliance&version=5_5" |jq
 func (c *Client) GetMajorVersionsSlice(slug string) (data []string, err error) {
-	c.EnsureProductDetailMap()
-	if err != nil {
-		return
-	}
+	return c.GetMajorVersionsSliceCtx(context.Background(), slug)
+}
 
-	if _, ok := ProductDetailMap[slug]; !ok {
-		err = ErrorInvalidSlug
+func (c *Client) GetMajorVersionsSliceCtx(ctx context.Context, slug string) (data []string, err error) {
+	ctx, span := c.startSpan(ctx, "GetMajorVersionsSlice", attr(AttrSlug, slug))
+	defer func() { endSpan(span, err) }()
+	var productDetails ProductDetails
+	if productDetails, err = c.lookupProduct(ctx, slug); err != nil {
 		return
 	}
 
 	search_string := fmt.Sprintf("?category=%s&product=%s&version=%s",
-		ProductDetailMap[slug].Category, slug, ProductDetailMap[slug].LatestMajorVersion)
+		productDetails.Category, slug, productDetails.LatestMajorVersion)
 
-	res, err := c.HttpClient.Get(majorVersionsURL + search_string)
+	res, err := c.getCached(ctx, c.endpoints().MajorVersions+search_string)
 	if err != nil {
 		return
 	}
@@ -10,16 +10,18 @@ import (
 )
 
 func TestGetMajorVersionsSuccess(t *testing.T) {
+	client := newFakeClient(t)
 	var majorVersions []string
-	majorVersions, err = basicClient.GetMajorVersionsSlice("vmware_tools")
+	majorVersions, err = client.GetMajorVersionsSlice("vmware_tools")
 	assert.Nil(t, err)
 	assert.Greater(t, len(majorVersions), 1, "Expected response to contain at least 1 item")
 	assert.Contains(t, majorVersions, "11_x")
 }
 
 func TestGetMajorVersionsInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var majorVersions []string
-	majorVersions, err = basicClient.GetMajorVersionsSlice("mware_tools")
+	majorVersions, err = client.GetMajorVersionsSlice("mware_tools")
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, majorVersions, "Expected response to be empty")
 }
@@ -0,0 +1,311 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LockfileVersion = 1
+	DefaultDlgType  = "PRODUCT_BINARY"
+)
+
+var (
+	ErrorInvalidManifest  = errors.New("manifest: invalid manifest")
+	ErrorLockfileVersion  = errors.New("lockfile: unsupported lockfile version")
+	ErrorLockfileMismatch = errors.New("lockfile: upstream no longer matches the lockfile")
+)
+
+// Manifest declares the files to download. It is resolved into a Lockfile, which pins the
+// exact files, so later runs download the same files.
+type Manifest struct {
+	Entries []ManifestEntry `json:"entries" yaml:"entries"`
+}
+
+type ManifestEntry struct {
+	Slug       string `json:"slug" yaml:"slug"`
+	SubProduct string `json:"subProduct" yaml:"subProduct"`
+	// Version is an exact version, a glob such as 8.0U* or a constraint such as ">=8.0 <8.1",
+	// see VersionConstraint. The newest matching version is used.
+	Version  string `json:"version" yaml:"version"`
+	FileGlob string `json:"fileGlob" yaml:"fileGlob"`
+	// Defaults to PRODUCT_BINARY
+	DlgType string `json:"dlgType,omitempty" yaml:"dlgType,omitempty"`
+}
+
+// Lockfile pins every file resolved from a Manifest
+type Lockfile struct {
+	LockfileVersion int          `json:"lockfileVersion"`
+	Files           []LockedFile `json:"files"`
+}
+
+type LockedFile struct {
+	Slug          string `json:"slug"`
+	SubProduct    string `json:"subProduct"`
+	DlgType       string `json:"dlgType"`
+	Version       string `json:"version"`
+	MajorVersion  string `json:"majorVersion"`
+	DownloadGroup string `json:"downloadGroup"`
+	ProductID     string `json:"productId"`
+	FileName      string `json:"fileName"`
+	UUID          string `json:"uuid"`
+	Build         string `json:"build"`
+	Sha256        string `json:"sha256"`
+}
+
+// ParseManifest reads a manifest in YAML or JSON. Unknown fields are rejected, so typos don't go unnoticed.
+func ParseManifest(data []byte) (manifest Manifest, err error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&manifest); err != nil {
+		err = fmt.Errorf("%w: %v", ErrorInvalidManifest, err)
+		return
+	}
+
+	for i, entry := range manifest.Entries {
+		if entry.Slug == "" || entry.SubProduct == "" || entry.Version == "" || entry.FileGlob == "" {
+			err = fmt.Errorf("%w: entry %d must have a slug, subProduct, version and fileGlob", ErrorInvalidManifest, i+1)
+			return
+		}
+	}
+	return
+}
+
+func LoadManifest(path string) (manifest Manifest, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return
+	}
+	return ParseManifest(data)
+}
+
+func LoadLockfile(path string) (lock Lockfile, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return
+	}
+	if err = json.Unmarshal(data, &lock); err != nil {
+		return
+	}
+	if lock.LockfileVersion != LockfileVersion {
+		err = fmt.Errorf("%w: %d", ErrorLockfileVersion, lock.LockfileVersion)
+	}
+	return
+}
+
+func WriteLockfile(path string, lock Lockfile) (err error) {
+	var data []byte
+	if data, err = json.MarshalIndent(lock, "", "  "); err != nil {
+		return
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ResolveManifest finds the files of every manifest entry. The client must be logged in.
+func (c *Client) ResolveManifest(ctx context.Context, manifest Manifest) (lock Lockfile, err error) {
+	ctx, span := c.startSpan(ctx, "ResolveManifest", attr(AttrFileCount, len(manifest.Entries)))
+	defer func() { endSpan(span, err) }()
+
+	lock.LockfileVersion = LockfileVersion
+	lock.Files = []LockedFile{}
+	for i, entry := range manifest.Entries {
+		var files []LockedFile
+		if files, err = c.resolveManifestEntry(ctx, entry); err != nil {
+			err = fmt.Errorf("manifest entry %d (%s %s %s %s): %w", i+1, entry.Slug, entry.SubProduct, entry.Version, entry.FileGlob, err)
+			return
+		}
+		lock.Files = append(lock.Files, files...)
+	}
+	return
+}
+
+func (c *Client) resolveManifestEntry(ctx context.Context, entry ManifestEntry) (files []LockedFile, err error) {
+	dlgType := entry.DlgType
+	if dlgType == "" {
+		dlgType = DefaultDlgType
+	}
+
+	var apiVersions APIVersions
+	if isVersionConstraint(entry.Version) {
+		apiVersions, _, err = c.FindVersionMatchingCtx(ctx, entry.Slug, entry.SubProduct, entry.Version, dlgType)
+	} else {
+		apiVersions, err = c.FindVersionCtx(ctx, entry.Slug, entry.SubProduct, entry.Version, dlgType)
+	}
+	if err != nil {
+		return
+	}
+
+	var dlgList DlgList
+	dlgList, err = c.GetSubProductDetailsCtx(ctx, entry.Slug, entry.SubProduct, apiVersions.MajorVersion, dlgType)
+	if err != nil {
+		return
+	}
+
+	var found FoundDownload
+	if found, err = c.FindDlgDetailsCtx(ctx, apiVersions.Code, dlgList.ProductID, entry.FileGlob); err != nil {
+		return
+	}
+
+	for _, details := range found.DownloadDetails {
+		files = append(files, LockedFile{
+			Slug:          entry.Slug,
+			SubProduct:    entry.SubProduct,
+			DlgType:       dlgType,
+			Version:       apiVersions.MinorVersion,
+			MajorVersion:  apiVersions.MajorVersion,
+			DownloadGroup: apiVersions.Code,
+			ProductID:     dlgList.ProductID,
+			FileName:      details.FileName,
+			UUID:          details.UUID,
+			Build:         details.Build,
+			Sha256:        details.Sha256Checksum,
+		})
+	}
+	return
+}
+
+// Exact versions and globs are left to FindVersion, anything else is a VersionConstraint.
+// Versions may contain spaces, e.g. "ESXi 8.0U2", so only operators mark a constraint.
+func isVersionConstraint(version string) bool {
+	return version == "latest" || strings.ContainsAny(version, "<>=!~^")
+}
+
+// VerifyLockfile checks that every locked file is still published unchanged.
+// All differences are returned, each wrapping ErrorLockfileMismatch.
+func (c *Client) VerifyLockfile(ctx context.Context, lock Lockfile) (err error) {
+	ctx, span := c.startSpan(ctx, "VerifyLockfile", attr(AttrFileCount, len(lock.Files)))
+	defer func() { endSpan(span, err) }()
+
+	var errs []error
+	for _, group := range groupLockedFiles(lock) {
+		var dlgDetails DlgDetails
+		if dlgDetails, err = c.GetDlgDetailsCtx(ctx, group.downloadGroup, group.productID); err != nil {
+			return
+		}
+		_, mismatches := matchLockedFiles(group.files, dlgDetails)
+		errs = append(errs, mismatches...)
+	}
+	return errors.Join(errs...)
+}
+
+// DownloadLockfile verifies the lockfile, then downloads every locked file into destDir, checking
+// it against the locked SHA-256 checksum. err is set when the files could not be requested, e.g. the
+// lockfile no longer matches or a EULA was not accepted, otherwise results holds the outcome of each file.
+func (c *Client) DownloadLockfile(ctx context.Context, lock Lockfile, destDir string, acceptEula bool, opts BatchOptions) (results []BatchResult, err error) {
+	ctx, span := c.startSpan(ctx, "DownloadLockfile", attr(AttrFileCount, len(lock.Files)))
+	defer func() {
+		spanErr := err
+		if spanErr == nil {
+			spanErr = BatchError(results)
+		}
+		endSpan(span, spanErr)
+	}()
+
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
+		return
+	}
+
+	var payloads []DownloadPayload
+	checksums := make(map[string]Checksums)
+	var errs []error
+	for _, group := range groupLockedFiles(lock) {
+		var dlgHeader DlgHeader
+		if dlgHeader, err = c.GetDlgHeaderCtx(ctx, group.downloadGroup, group.productID); err != nil {
+			return
+		}
+		var dlgDetails DlgDetails
+		if dlgDetails, err = c.GetDlgDetailsCtx(ctx, group.downloadGroup, group.productID); err != nil {
+			return
+		}
+
+		detailsByUUID, mismatches := matchLockedFiles(group.files, dlgDetails)
+		if len(mismatches) > 0 {
+			errs = append(errs, mismatches...)
+			continue
+		}
+
+		if !dlgDetails.EligibilityResponse.EligibleToDownload {
+			err = fmt.Errorf("%w: %s", ErrorNotEntitled, group.downloadGroup)
+			return
+		}
+		if !dlgDetails.EulaResponse.EulaAccepted {
+			if !acceptEula {
+				err = fmt.Errorf("%w: %s, see %s", ErrorEulaUnaccepted, group.downloadGroup, dlgDetails.EulaResponse.EulaURL)
+				return
+			}
+			if err = c.AcceptEulaCtx(ctx, group.downloadGroup, group.productID); err != nil {
+				return
+			}
+		}
+
+		for _, file := range group.files {
+			payloads = append(payloads, newDownloadPayload(dlgHeader, group.downloadGroup, group.productID, detailsByUUID[file.UUID]))
+			// Without a locked checksum the MD5 checksum in the payload is still verified
+			if file.Sha256 != "" {
+				checksums[file.UUID] = Checksums{Sha256: file.Sha256}
+			}
+		}
+	}
+	if err = errors.Join(errs...); err != nil {
+		return
+	}
+
+	opts.Checksums = checksums
+	results = c.DownloadBatch(ctx, payloads, destDir, opts)
+	return
+}
+
+type lockedGroup struct {
+	downloadGroup string
+	productID     string
+	files         []LockedFile
+}
+
+// Group locked files by download group, keeping the order of the lockfile
+func groupLockedFiles(lock Lockfile) (groups []*lockedGroup) {
+	index := make(map[string]*lockedGroup)
+	for _, file := range lock.Files {
+		key := file.DownloadGroup + "/" + file.ProductID
+		group, ok := index[key]
+		if !ok {
+			group = &lockedGroup{downloadGroup: file.DownloadGroup, productID: file.ProductID}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.files = append(group.files, file)
+	}
+	return
+}
+
+// Compare locked files with the files currently published in their download group
+func matchLockedFiles(files []LockedFile, dlgDetails DlgDetails) (detailsByUUID map[string]DownloadDetails, mismatches []error) {
+	detailsByUUID = make(map[string]DownloadDetails)
+	for _, details := range dlgDetails.DownloadDetails {
+		detailsByUUID[details.UUID] = details
+	}
+
+	for _, file := range files {
+		details, ok := detailsByUUID[file.UUID]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Errorf("%w: %s (%s) is no longer published in %s", ErrorLockfileMismatch, file.FileName, file.UUID, file.DownloadGroup))
+		case details.FileName != file.FileName:
+			mismatches = append(mismatches, fmt.Errorf("%w: %s was renamed to %s", ErrorLockfileMismatch, file.FileName, details.FileName))
+		case details.Build != file.Build:
+			mismatches = append(mismatches, fmt.Errorf("%w: %s build changed from %s to %s", ErrorLockfileMismatch, file.FileName, file.Build, details.Build))
+		case !strings.EqualFold(details.Sha256Checksum, file.Sha256):
+			mismatches = append(mismatches, fmt.Errorf("%w: %s sha256 changed from %s to %s", ErrorLockfileMismatch, file.FileName, file.Sha256, details.Sha256Checksum))
+		}
+	}
+	return
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+const testManifest = `
+entries:
+  - slug: vmware_tools
+    subProduct: vmtools
+    version: "11.*"
+    fileGlob: VMware-Tools-darwin-*
+  - slug: vmware_vsphere
+    subProduct: esxi
+    version: ">=8.0 <8.0U2"
+    fileGlob: "*.iso"
+`
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest([]byte(testManifest))
+	require.Nil(t, err)
+	require.Len(t, manifest.Entries, 2)
+	assert.Equal(t, ManifestEntry{Slug: "vmware_tools", SubProduct: "vmtools", Version: "11.*", FileGlob: "VMware-Tools-darwin-*"}, manifest.Entries[0])
+
+	fromJSON, err := ParseManifest([]byte(`{"entries": [{"slug": "vmware_tools", "subProduct": "vmtools", "version": "11.*", "fileGlob": "VMware-Tools-darwin-*"}]}`))
+	require.Nil(t, err)
+	assert.Equal(t, manifest.Entries[:1], fromJSON.Entries)
+
+	_, err = ParseManifest([]byte("entries:\n  - slug: vmware_tools\n    subproduct: vmtools\n"))
+	assert.ErrorIs(t, err, ErrorInvalidManifest)
+
+	_, err = ParseManifest([]byte("entries:\n  - slug: vmware_tools\n    subProduct: vmtools\n"))
+	require.ErrorIs(t, err, ErrorInvalidManifest)
+	assert.Contains(t, err.Error(), "entry 1")
+}
+
+func resolveTestManifest(t *testing.T, client *Client) Lockfile {
+	t.Helper()
+
+	manifest, err := ParseManifest([]byte(testManifest))
+	require.Nil(t, err)
+	lock, err := client.ResolveManifest(context.Background(), manifest)
+	require.Nil(t, err)
+	return lock
+}
+
+func TestResolveManifest(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	lock := resolveTestManifest(t, client)
+
+	assert.Equal(t, LockfileVersion, lock.LockfileVersion)
+	require.Len(t, lock.Files, 2)
+	assert.Equal(t, LockedFile{
+		Slug:          "vmware_tools",
+		SubProduct:    "vmtools",
+		DlgType:       "PRODUCT_BINARY",
+		Version:       "11.3.5",
+		MajorVersion:  "11_x",
+		DownloadGroup: "VMTOOLS1135",
+		ProductID:     "1073",
+		FileName:      "VMware-Tools-darwin-11.3.5-18557794.tar.gz",
+		UUID:          lock.Files[0].UUID,
+		Build:         "18557794",
+		Sha256:        lock.Files[0].Sha256,
+	}, lock.Files[0])
+	assert.NotEmpty(t, lock.Files[0].Sha256)
+	assert.Equal(t, "8.0U1c", lock.Files[1].Version)
+	assert.Equal(t, "VMware-VMvisor-Installer-8.0U1c-22088125.x86_64.iso", lock.Files[1].FileName)
+}
+
+func TestResolveManifestInvalidEntry(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+
+	_, err := client.ResolveManifest(context.Background(), Manifest{Entries: []ManifestEntry{
+		{Slug: "vmware_tools", SubProduct: "vmtools", Version: "11.*", FileGlob: "*.exe"},
+	}})
+	require.ErrorIs(t, err, ErrorNoMatchingFiles)
+	assert.Contains(t, err.Error(), "manifest entry 1")
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	lock := resolveTestManifest(t, client)
+
+	path := filepath.Join(t.TempDir(), "vcc.lock.json")
+	require.Nil(t, WriteLockfile(path, lock))
+	loaded, err := LoadLockfile(path)
+	require.Nil(t, err)
+	assert.Equal(t, lock, loaded)
+
+	lock.LockfileVersion = 2
+	data, _ := json.Marshal(lock)
+	require.Nil(t, os.WriteFile(path, data, 0644))
+	_, err = LoadLockfile(path)
+	assert.ErrorIs(t, err, ErrorLockfileVersion)
+}
+
+func TestVerifyLockfile(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	lock := resolveTestManifest(t, client)
+	assert.Nil(t, client.VerifyLockfile(context.Background(), lock))
+
+	// Republish the ISO with a new checksum
+	fixtures := fakecc.DefaultFixtures()
+	details := fixtures.DlgDetails["ESXI80U1C/1345"]
+	details.DownloadFiles = bytes.Replace(details.DownloadFiles, []byte(lock.Files[1].Sha256), []byte("0123"), 1)
+	fixtures.DlgDetails["ESXI80U1C/1345"] = details
+	srv := fakecc.NewServer(fixtures)
+	defer srv.Close()
+	client = fakeLogin(t, srv)
+
+	err := client.VerifyLockfile(context.Background(), lock)
+	require.ErrorIs(t, err, ErrorLockfileMismatch)
+	assert.Contains(t, err.Error(), "VMware-VMvisor-Installer-8.0U1c-22088125.x86_64.iso sha256 changed")
+
+	_, err = client.DownloadLockfile(context.Background(), lock, t.TempDir(), false, BatchOptions{})
+	assert.ErrorIs(t, err, ErrorLockfileMismatch)
+}
+
+func TestDownloadLockfile(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	lock := resolveTestManifest(t, client)
+
+	dir := t.TempDir()
+	results, err := client.DownloadLockfile(context.Background(), lock, dir, false, BatchOptions{})
+	require.Nil(t, err)
+	require.Nil(t, BatchError(results))
+	require.Len(t, results, 2)
+	for _, file := range lock.Files {
+		assert.FileExists(t, filepath.Join(dir, file.FileName))
+	}
+}
+
+func TestDownloadLockfileEula(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	lock, err := client.ResolveManifest(context.Background(), Manifest{Entries: []ManifestEntry{
+		{Slug: "vmware_tools", SubProduct: "vmtools", Version: "11.1.0", FileGlob: "VMware-Tools-darwin-*"},
+	}})
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	_, err = client.DownloadLockfile(context.Background(), lock, dir, false, BatchOptions{})
+	assert.ErrorIs(t, err, ErrorEulaUnaccepted)
+
+	results, err := client.DownloadLockfile(context.Background(), lock, dir, true, BatchOptions{})
+	require.Nil(t, err)
+	assert.Nil(t, BatchError(results))
+}
+
+func TestDownloadLockfileWithoutSha256(t *testing.T) {
+	// A file published without a SHA-256 checksum, whose content no longer matches its MD5 checksum
+	fixtures := fakecc.DefaultFixtures()
+	const key, uuid = "VMTOOLS1135/1073", "00000007-0000-4000-8000-000000000007"
+	details := fixtures.DlgDetails[key]
+	details.DownloadFiles = json.RawMessage(strings.Replace(string(details.DownloadFiles), "7f9ba3733477c7a9f59cd5ad78e5bc07d633ba28effad035108a245f02b15a47", "", 1))
+	fixtures.DlgDetails[key] = details
+	file := fixtures.Files[uuid]
+	file.Content += "corrupt"
+	fixtures.Files[uuid] = file
+
+	srv := fakecc.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+	client := fakeLogin(t, srv)
+
+	lock, err := client.ResolveManifest(context.Background(), Manifest{Entries: []ManifestEntry{
+		{Slug: "vmware_tools", SubProduct: "vmtools", Version: "11.3.5", FileGlob: "VMware-Tools-darwin-*"},
+	}})
+	require.Nil(t, err)
+	require.Len(t, lock.Files, 1)
+	assert.Empty(t, lock.Files[0].Sha256)
+
+	results, err := client.DownloadLockfile(context.Background(), lock, t.TempDir(), false, BatchOptions{})
+	require.Nil(t, err)
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Err, ErrorChecksumMismatch)
+}
+
+func TestIsVersionConstraint(t *testing.T) {
+	assert.True(t, isVersionConstraint(">=8.0 <8.0U2"))
+	assert.True(t, isVersionConstraint("~8.0U2"))
+	assert.True(t, isVersionConstraint("latest"))
+	assert.False(t, isVersionConstraint("8.0U2"))
+	assert.False(t, isVersionConstraint("8.0U*"))
+	assert.False(t, isVersionConstraint("ESXi 8.0U2"))
+}
@@ -0,0 +1,305 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	MirrorIndexFile    = "index.json"
+	MirrorIndexVersion = 1
+)
+
+var ErrorMirrorIndexVersion = errors.New("mirror: unsupported index version")
+
+// MirrorTarget selects the part of the catalog to mirror
+type MirrorTarget struct {
+	Slug string
+	// SubProducts to mirror. Empty mirrors every sub-product.
+	SubProducts []string
+	// Defaults to PRODUCT_BINARY
+	DlgTypes []string
+	// Versions is a VersionConstraint limiting the versions mirrored. Empty mirrors every version.
+	Versions string
+}
+
+type MirrorOptions struct {
+	// Accept the EULA of download groups which have not been accepted yet.
+	// Otherwise their files fail with ErrorEulaUnaccepted.
+	AcceptEula bool
+	Batch      BatchOptions
+}
+
+// MirrorIndex describes every file in a mirror. It is kept in index.json at the root of the mirror.
+type MirrorIndex struct {
+	IndexVersion int          `json:"indexVersion"`
+	Files        []MirrorFile `json:"files"`
+}
+
+type MirrorFile struct {
+	// Path of the file relative to the mirror, always using forward slashes
+	Path          string          `json:"path"`
+	Slug          string          `json:"slug"`
+	SubProduct    string          `json:"subProduct"`
+	DlgType       string          `json:"dlgType"`
+	Version       string          `json:"version"`
+	MajorVersion  string          `json:"majorVersion"`
+	DownloadGroup string          `json:"downloadGroup"`
+	ProductID     string          `json:"productId"`
+	Details       DownloadDetails `json:"details"`
+}
+
+type MirrorResult struct {
+	// Number of files which were already present
+	Present int
+	// Outcome of every file which had to be downloaded. Files which could not be requested,
+	// e.g. because the user is not entitled to them, are included with their error.
+	Downloads []BatchResult
+}
+
+// Files of a download group which are missing from the mirror
+type mirrorGroup struct {
+	dir        string
+	dlgDetails DlgDetails
+	files      []MirrorFile
+}
+
+// LoadMirrorIndex reads the index of the mirror in dir, returning an empty index for a new mirror
+func LoadMirrorIndex(dir string) (index MirrorIndex, err error) {
+	index.IndexVersion = MirrorIndexVersion
+	index.Files = []MirrorFile{}
+
+	var data []byte
+	data, err = os.ReadFile(filepath.Join(dir, MirrorIndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	} else if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, &index); err != nil {
+		return
+	}
+	if index.IndexVersion != MirrorIndexVersion {
+		err = fmt.Errorf("%w: %d", ErrorMirrorIndexVersion, index.IndexVersion)
+	}
+	return
+}
+
+func writeMirrorIndex(dir string, files map[string]MirrorFile) (err error) {
+	index := MirrorIndex{IndexVersion: MirrorIndexVersion, Files: make([]MirrorFile, 0, len(files))}
+	for _, file := range files {
+		index.Files = append(index.Files, file)
+	}
+	sort.Slice(index.Files, func(i, j int) bool { return index.Files[i].Path < index.Files[j].Path })
+
+	var data []byte
+	if data, err = json.MarshalIndent(index, "", "  "); err != nil {
+		return
+	}
+	path := filepath.Join(dir, MirrorIndexFile)
+	if err = writeFileAtomic(path, append(data, '\n')); err != nil {
+		return
+	}
+	// Temporary files are private, but the index is as readable as the mirror
+	return os.Chmod(path, 0644)
+}
+
+// Mirror downloads the files of targets into dir, laid out as <slug>/<subproduct>/<version>/<file>.
+// Files already in the index with the same UUID and SHA-256 are skipped, so running it again only
+// downloads what is new. The index is saved after every download group and files are only moved
+// into place once verified, so an interrupted run is picked up by the next one.
+func (c *Client) Mirror(ctx context.Context, dir string, targets []MirrorTarget, opts MirrorOptions) (result MirrorResult, err error) {
+	ctx, span := c.startSpan(ctx, "Mirror")
+	defer func() { endSpan(span, err) }()
+	if err = c.CheckLoggedInCtx(ctx); err != nil {
+		return
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	var index MirrorIndex
+	if index, err = LoadMirrorIndex(dir); err != nil {
+		return
+	}
+	files := make(map[string]MirrorFile)
+	for _, file := range index.Files {
+		files[file.Details.UUID] = file
+	}
+
+	for _, target := range targets {
+		var groups []mirrorGroup
+		if groups, err = c.mirrorTarget(ctx, dir, target, files, &result); err != nil {
+			return
+		}
+		for _, group := range groups {
+			results := c.mirrorGroup(ctx, dir, group, opts)
+			for i, res := range results {
+				if res.Err != nil {
+					continue
+				}
+				// The file is named by the download link, which may differ from the catalog
+				file := group.files[i]
+				if rel, relErr := filepath.Rel(dir, res.Path); relErr == nil {
+					file.Path = filepath.ToSlash(rel)
+				}
+				files[file.Details.UUID] = file
+			}
+			result.Downloads = append(result.Downloads, results...)
+			if err = writeMirrorIndex(dir, files); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// mirrorTarget walks the catalog of a target, returning the files missing from the mirror grouped by download group
+func (c *Client) mirrorTarget(ctx context.Context, dir string, target MirrorTarget, present map[string]MirrorFile, result *MirrorResult) (groups []mirrorGroup, err error) {
+	versions := target.Versions
+	if versions == "" {
+		versions = "*"
+	}
+	var constraint VersionConstraint
+	if constraint, err = ParseVersionConstraint(versions); err != nil {
+		return
+	}
+
+	dlgTypes := target.DlgTypes
+	if len(dlgTypes) == 0 {
+		dlgTypes = []string{DefaultDlgType}
+	}
+
+	for _, dlgType := range dlgTypes {
+		var subProductMap map[string]SubProductDetails
+		if subProductMap, err = c.GetSubProductsMapCtx(ctx, target.Slug, dlgType, ""); err != nil {
+			return
+		}
+
+		subProducts := target.SubProducts
+		if len(subProducts) == 0 {
+			for subProduct := range subProductMap {
+				subProducts = append(subProducts, subProduct)
+			}
+			sort.Strings(subProducts)
+		}
+
+		for _, subProduct := range subProducts {
+			subProductDetails, ok := subProductMap[subProduct]
+			if !ok {
+				err = fmt.Errorf("%w: %s %s", ErrorInvalidSubProduct, target.Slug, subProduct)
+				return
+			}
+
+			var versionMap map[string]APIVersions
+			if versionMap, err = c.GetVersionMapCtx(ctx, target.Slug, subProduct, dlgType); err != nil {
+				return
+			}
+
+			for _, apiVersions := range matchVersionConstraint(constraint, versionMap) {
+				productID := subProductDetails.DlgListByVersion[apiVersions.MajorVersion].ProductID
+				var dlgDetails DlgDetails
+				if dlgDetails, err = c.GetDlgDetailsCtx(ctx, apiVersions.Code, productID); err != nil {
+					return
+				}
+
+				group := mirrorGroup{
+					dir:        filepath.Join(target.Slug, subProduct, safePathElement(apiVersions.MinorVersion)),
+					dlgDetails: dlgDetails,
+				}
+				for _, details := range dlgDetails.DownloadDetails {
+					if details.FileName == "" {
+						continue
+					}
+					file := MirrorFile{
+						Path:          filepath.ToSlash(filepath.Join(group.dir, filepath.Base(details.FileName))),
+						Slug:          target.Slug,
+						SubProduct:    subProduct,
+						DlgType:       dlgType,
+						Version:       apiVersions.MinorVersion,
+						MajorVersion:  apiVersions.MajorVersion,
+						DownloadGroup: apiVersions.Code,
+						ProductID:     productID,
+						Details:       details,
+					}
+					if isMirrored(dir, present[details.UUID], details) {
+						result.Present++
+						continue
+					}
+					group.files = append(group.files, file)
+				}
+				if len(group.files) > 0 {
+					groups = append(groups, group)
+				}
+			}
+		}
+	}
+	return
+}
+
+func isMirrored(dir string, file MirrorFile, details DownloadDetails) bool {
+	if file.Details.UUID != details.UUID || !strings.EqualFold(file.Details.Sha256Checksum, details.Sha256Checksum) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	return err == nil
+}
+
+// Version names are used as directory names
+func safePathElement(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+}
+
+// mirrorGroup downloads the missing files of a download group, returning a result for every file
+func (c *Client) mirrorGroup(ctx context.Context, dir string, group mirrorGroup, opts MirrorOptions) (results []BatchResult) {
+	first := group.files[0]
+	dlgDetails := group.dlgDetails
+	if !dlgDetails.EligibilityResponse.EligibleToDownload {
+		return failedMirrorFiles(group, ErrorNotEntitled)
+	}
+	if !dlgDetails.EulaResponse.EulaAccepted {
+		if !opts.AcceptEula {
+			return failedMirrorFiles(group, fmt.Errorf("%w: see %s", ErrorEulaUnaccepted, dlgDetails.EulaResponse.EulaURL))
+		}
+		if err := c.AcceptEulaCtx(ctx, first.DownloadGroup, first.ProductID); err != nil {
+			return failedMirrorFiles(group, err)
+		}
+	}
+
+	dlgHeader, err := c.GetDlgHeaderCtx(ctx, first.DownloadGroup, first.ProductID)
+	if err != nil {
+		return failedMirrorFiles(group, err)
+	}
+
+	destDir := filepath.Join(dir, group.dir)
+	if err = os.MkdirAll(destDir, 0755); err != nil {
+		return failedMirrorFiles(group, err)
+	}
+
+	payloads := make([]DownloadPayload, len(group.files))
+	batchOpts := opts.Batch
+	batchOpts.Checksums = make(map[string]Checksums)
+	for i, file := range group.files {
+		payloads[i] = newDownloadPayload(dlgHeader, file.DownloadGroup, file.ProductID, file.Details)
+		batchOpts.Checksums[file.Details.UUID] = ChecksumsFromDetails(file.Details)
+	}
+	return c.DownloadBatch(ctx, payloads, destDir, batchOpts)
+}
+
+func failedMirrorFiles(group mirrorGroup, err error) (results []BatchResult) {
+	results = make([]BatchResult, len(group.files))
+	for i, file := range group.files {
+		results[i] = BatchResult{FileName: file.Details.FileName, Err: err}
+	}
+	return
+}
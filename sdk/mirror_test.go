@@ -0,0 +1,161 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+var testMirrorTargets = []MirrorTarget{{
+	Slug:        "vmware_tools",
+	SubProducts: []string{"vmtools"},
+	Versions:    ">=11.1.1 <12",
+}}
+
+func TestMirror(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	dir := t.TempDir()
+
+	result, err := client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+	require.Nil(t, BatchError(result.Downloads))
+	assert.Equal(t, 0, result.Present)
+	assert.Len(t, result.Downloads, 6)
+	assert.FileExists(t, filepath.Join(dir, "vmware_tools", "vmtools", "11.3.5", "VMware-Tools-darwin-11.3.5-18557794.tar.gz"))
+	assert.FileExists(t, filepath.Join(dir, "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-windows-11.1.1-16303738.zip"))
+
+	index, err := LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	require.Len(t, index.Files, 6)
+	file := index.Files[0]
+	assert.Equal(t, "vmware_tools/vmtools/11.1.1/VMware-Tools-11.1.1-core-offline-depot-ESXi-all-16303738.zip", file.Path)
+	assert.Equal(t, "11_x", file.MajorVersion)
+	assert.Equal(t, "VMTOOLS1111", file.DownloadGroup)
+	assert.Equal(t, "16303738", file.Details.Build)
+	assert.NotEmpty(t, file.Details.Sha256Checksum)
+
+	// Nothing is downloaded again
+	result, err = client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 6, result.Present)
+	assert.Empty(t, result.Downloads)
+}
+
+func TestMirrorChangedChecksum(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	dir := t.TempDir()
+
+	_, err := client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+
+	// A file whose checksum differs from the catalog is fetched again
+	index, err := LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	index.Files[0].Details.Sha256Checksum = "0123"
+	data, _ := json.Marshal(index)
+	require.Nil(t, os.WriteFile(filepath.Join(dir, MirrorIndexFile), data, 0644))
+
+	result, err := client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 5, result.Present)
+	require.Len(t, result.Downloads, 1)
+	assert.Nil(t, result.Downloads[0].Err)
+
+	index, err = LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	assert.NotEqual(t, "0123", index.Files[0].Details.Sha256Checksum)
+}
+
+func TestMirrorInterrupted(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	dir := t.TempDir()
+
+	_, err := client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+
+	// Files downloaded before the index was saved are verified and added to the index
+	require.Nil(t, os.Remove(filepath.Join(dir, MirrorIndexFile)))
+	partial := filepath.Join(dir, "vmware_tools", "vmtools", "11.3.5", "VMware-Tools-darwin-11.3.5-18557794.tar.gz")
+	require.Nil(t, os.Rename(partial, partial+partialSuffix))
+
+	result, err := client.Mirror(context.Background(), dir, testMirrorTargets, MirrorOptions{})
+	require.Nil(t, err)
+	assert.Nil(t, BatchError(result.Downloads))
+	assert.FileExists(t, partial)
+	assert.NoFileExists(t, partial+partialSuffix)
+
+	index, err := LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	assert.Len(t, index.Files, 6)
+}
+
+func TestMirrorRenamedDownload(t *testing.T) {
+	// The download link names the file differently from the catalog
+	fixtures := fakecc.DefaultFixtures()
+	const uuid = "00000007-0000-4000-8000-000000000007"
+	file := fixtures.Files[uuid]
+	file.FileName = "VMware-Tools-darwin-11.3.5.tar.gz"
+	fixtures.Files[uuid] = file
+	srv := fakecc.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	client := fakeLogin(t, srv)
+	dir := t.TempDir()
+	targets := []MirrorTarget{{Slug: "vmware_tools", SubProducts: []string{"vmtools"}, Versions: "11.3.5"}}
+
+	result, err := client.Mirror(context.Background(), dir, targets, MirrorOptions{})
+	require.Nil(t, err)
+	require.Nil(t, BatchError(result.Downloads))
+
+	index, err := LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	var paths []string
+	for _, file := range index.Files {
+		paths = append(paths, file.Path)
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file.Path)))
+	}
+	assert.Contains(t, paths, "vmware_tools/vmtools/11.3.5/VMware-Tools-darwin-11.3.5.tar.gz")
+
+	// The renamed file is found on the next run
+	result, err = client.Mirror(context.Background(), dir, targets, MirrorOptions{})
+	require.Nil(t, err)
+	assert.Empty(t, result.Downloads)
+}
+
+func TestMirrorEula(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+	dir := t.TempDir()
+	targets := []MirrorTarget{{Slug: "vmware_tools", SubProducts: []string{"vmtools"}, Versions: "11.1.0"}}
+
+	result, err := client.Mirror(context.Background(), dir, targets, MirrorOptions{})
+	require.Nil(t, err)
+	require.Len(t, result.Downloads, 3)
+	assert.ErrorIs(t, BatchError(result.Downloads), ErrorEulaUnaccepted)
+	index, err := LoadMirrorIndex(dir)
+	require.Nil(t, err)
+	assert.Empty(t, index.Files)
+
+	result, err = client.Mirror(context.Background(), dir, targets, MirrorOptions{AcceptEula: true})
+	require.Nil(t, err)
+	assert.Nil(t, BatchError(result.Downloads))
+}
+
+func TestMirrorInvalidTarget(t *testing.T) {
+	client := fakeLogin(t, newFakeServer(t))
+
+	_, err := client.Mirror(context.Background(), t.TempDir(), []MirrorTarget{{Slug: "vmware_tools", SubProducts: []string{"unknown"}}}, MirrorOptions{})
+	assert.ErrorIs(t, err, ErrorInvalidSubProduct)
+
+	_, err = client.Mirror(context.Background(), t.TempDir(), []MirrorTarget{{Slug: "vmware_tools", Versions: ">="}}, MirrorOptions{})
+	assert.ErrorIs(t, err, ErrorInvalidVersionConstraint)
+}
@@ -4,17 +4,16 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
 )
 
 const (
-	productsURL = baseURL + "/channel/public/api/v1.0/products/getProductsAtoZ?isPrivate=true"
+	productsPath = "/channel/public/api/v1.0/products/getProductsAtoZ?isPrivate=true"
 )
 
-var ProductDetailMap map[string]ProductDetails
-
 type ProductDetails struct {
 	Category           string
 	DisplayName        string
@@ -39,8 +38,14 @@ type ProductCategoryList struct {
 }
 
 func (c *Client) GetProductsSlice() (data []MajorProducts, err error) {
+	return c.GetProductsSliceCtx(context.Background())
+}
+
+func (c *Client) GetProductsSliceCtx(ctx context.Context) (data []MajorProducts, err error) {
+	ctx, span := c.startSpan(ctx, "GetProductsSlice")
+	defer func() { endSpan(span, err) }()
 	var res *http.Response
-	res, err = c.HttpClient.Get(productsURL)
+	res, err = c.getCached(ctx, c.endpoints().Products)
 	if err != nil {
 		return
 	}
@@ -58,10 +63,20 @@ func (c *Client) GetProductsSlice() (data []MajorProducts, err error) {
 
 // returned map is used to look up products by their slig
 func (c *Client) GetProductsMap() (productMap map[string]ProductDetails, err error) {
+	return c.GetProductsMapCtx(context.Background())
+}
+
+func (c *Client) GetProductsMapCtx(ctx context.Context) (productMap map[string]ProductDetails, err error) {
 	productMap = make(map[string]ProductDetails)
+	if c.snapshot != nil {
+		for slug, productDetails := range c.snapshot.Products {
+			productMap[slug] = productDetails
+		}
+		return
+	}
 
 	var products []MajorProducts
-	products, err = c.GetProductsSlice()
+	products, err = c.GetProductsSliceCtx(ctx)
 	if err != nil {
 		return
 	}
@@ -82,24 +97,85 @@ func (c *Client) GetProductsMap() (productMap map[string]ProductDetails, err err
 	return
 }
 
+// EnsureProductDetailMap loads the product catalog of the client, unless already loaded.
+// The catalog is held per client and is safe to load from multiple goroutines.
 func (c *Client) EnsureProductDetailMap() (err error) {
-	if len(ProductDetailMap) < 1 {
-		ProductDetailMap, err = c.GetProductsMap()
+	return c.EnsureProductDetailMapCtx(context.Background())
+}
+
+func (c *Client) EnsureProductDetailMapCtx(ctx context.Context) (err error) {
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+
+	if len(c.catalog) < 1 {
+		c.catalog, err = c.GetProductsMapCtx(ctx)
 	}
 	return
 }
 
-func (c *Client) GetCategory(slug string) (data string, err error) {
-	c.EnsureProductDetailMap()
-	if err != nil {
+// RefreshCatalog reloads the product catalog, bypassing any cached response
+func (c *Client) RefreshCatalog() (err error) {
+	return c.RefreshCatalogCtx(context.Background())
+}
+
+func (c *Client) RefreshCatalogCtx(ctx context.Context) (err error) {
+	ctx, span := c.startSpan(ctx, "RefreshCatalog")
+	defer func() { endSpan(span, err) }()
+	var catalog map[string]ProductDetails
+	if catalog, err = c.GetProductsMapCtx(withCacheRefresh(ctx)); err != nil {
+		return
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	c.catalog = catalog
+	return
+}
+
+// ProductDetailMap returns a copy of the product catalog, keyed by slug
+func (c *Client) ProductDetailMap() (productMap map[string]ProductDetails, err error) {
+	return c.ProductDetailMapCtx(context.Background())
+}
+
+func (c *Client) ProductDetailMapCtx(ctx context.Context) (productMap map[string]ProductDetails, err error) {
+	if err = c.EnsureProductDetailMapCtx(ctx); err != nil {
 		return
 	}
 
-	if category, ok := ProductDetailMap[slug]; ok {
-		data = category.Category
-	} else {
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	productMap = make(map[string]ProductDetails, len(c.catalog))
+	for slug, productDetails := range c.catalog {
+		productMap[slug] = productDetails
+	}
+	return
+}
+
+// Look up a product in the catalog, loading the catalog when needed
+func (c *Client) lookupProduct(ctx context.Context, slug string) (productDetails ProductDetails, err error) {
+	if err = c.EnsureProductDetailMapCtx(ctx); err != nil {
+		return
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	productDetails, ok := c.catalog[slug]
+	if !ok {
 		err = ErrorInvalidSlug
 	}
+	return
+}
+
+func (c *Client) GetCategory(slug string) (data string, err error) {
+	return c.GetCategoryCtx(context.Background(), slug)
+}
+
+func (c *Client) GetCategoryCtx(ctx context.Context, slug string) (data string, err error) {
+	var productDetails ProductDetails
+	if productDetails, err = c.lookupProduct(ctx, slug); err != nil {
+		return
+	}
+	data = productDetails.Category
 
 	return
 }
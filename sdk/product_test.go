@@ -4,20 +4,76 @@
 package sdk
 
 import (
-	"github.com/stretchr/testify/assert"
+	"encoding/json"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
 )
 
+const productsTestPath = "/channel/public/api/v1.0/products/getProductsAtoZ"
+
 func TestGetProducts(t *testing.T) {
+	client := newFakeClient(t)
 	var products []MajorProducts
-	products, err = basicClient.GetProductsSlice()
+	products, err = client.GetProductsSlice()
 	assert.Nil(t, err)
-	assert.Greater(t, len(products), 80, "Expected response to contain at least 80 items")
+	assert.Len(t, products, 3)
 }
 
 func TestGetProductMap(t *testing.T) {
+	client := newFakeClient(t)
 	var products map[string]ProductDetails
-	products, err = basicClient.GetProductsMap()
+	products, err = client.GetProductsMap()
 	assert.Nil(t, err)
 	assert.Contains(t, products, "vmware_tools")
 }
+
+func TestProductCatalogPerClient(t *testing.T) {
+	srv := newFakeServer(t)
+
+	// The second server only knows about vSphere
+	fixtures := fakecc.DefaultFixtures()
+	fixtures.Products = json.RawMessage(`{"productCategoryList":[{"productList":[{"name":"VMware vSphere","actions":[{"target":"./info/slug/datacenter_cloud_infrastructure/vmware_vsphere/8_0"}]}]}]}`)
+	otherSrv := fakecc.NewServer(fixtures)
+	defer otherSrv.Close()
+
+	client := NewClient(fakeClientOptions(srv))
+	otherClient := NewClient(fakeClientOptions(otherSrv))
+
+	_, err = client.GetCategory("vmware_tools")
+	assert.Nil(t, err)
+	_, err = otherClient.GetCategory("vmware_tools")
+	assert.ErrorIs(t, err, ErrorInvalidSlug)
+
+	productMap, err := otherClient.ProductDetailMap()
+	assert.Nil(t, err)
+	assert.Len(t, productMap, 1)
+	assert.Equal(t, "8_0", productMap["vmware_vsphere"].LatestMajorVersion)
+}
+
+func TestRefreshCatalog(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.Cache = NewMemoryCache()
+	client := NewClient(opts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, client.EnsureProductDetailMap())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, srv.RequestCount(productsTestPath))
+
+	// Refreshing bypasses the cache
+	assert.Nil(t, client.RefreshCatalog())
+	assert.Equal(t, 2, srv.RequestCount(productsTestPath))
+	_, err = client.GetCategory("vmware_tools")
+	assert.Nil(t, err)
+}
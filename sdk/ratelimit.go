@@ -0,0 +1,128 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type RateLimit struct {
+	// Average number of requests per second
+	RequestsPerSecond float64
+	// Number of requests which can be sent at once before the rate applies. Defaults to 1.
+	Burst int
+	// Give each host its own limit, so e.g. downloads do not delay catalog requests
+	PerHost bool
+}
+
+// RateLimitStats reports how much the limiter has slowed requests down
+type RateLimitStats struct {
+	Requests int64
+	// Requests which had to wait for the limiter
+	Delayed  int64
+	WaitTime time.Duration
+}
+
+// RateLimiter is a token bucket enforced on every request made by the clients it is passed to,
+// see ClientOptions.RateLimiter. It can be shared by several clients to apply a combined limit.
+type RateLimiter struct {
+	limit RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	stats   RateLimitStats
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &RateLimiter{
+		limit:   limit,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+		sleep:   sleepCtx,
+	}
+}
+
+func (r *RateLimiter) Stats() RateLimitStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Wait blocks until a request to host is allowed, or ctx is done
+func (r *RateLimiter) Wait(ctx context.Context, host string) (err error) {
+	if r.limit.RequestsPerSecond <= 0 {
+		return
+	}
+	if !r.limit.PerHost {
+		host = ""
+	}
+
+	r.mu.Lock()
+	now := r.now()
+	bucket, ok := r.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(r.limit.Burst), last: now}
+		r.buckets[host] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * r.limit.RequestsPerSecond
+	if bucket.tokens > float64(r.limit.Burst) {
+		bucket.tokens = float64(r.limit.Burst)
+	}
+	bucket.last = now
+
+	// Take the token now, so waiting requests are served in order
+	bucket.tokens--
+	var wait time.Duration
+	if bucket.tokens < 0 {
+		wait = time.Duration(-bucket.tokens / r.limit.RequestsPerSecond * float64(time.Second))
+	}
+	r.stats.Requests++
+	if wait > 0 {
+		r.stats.Delayed++
+	}
+	r.mu.Unlock()
+
+	if wait == 0 {
+		return
+	}
+
+	start := r.now()
+	err = r.sleep(ctx, wait)
+
+	r.mu.Lock()
+	r.stats.WaitTime += r.now().Sub(start)
+	if err != nil {
+		// Return the unused token
+		bucket.tokens++
+	}
+	r.mu.Unlock()
+	return
+}
+
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	if err = t.limiter.Wait(req.Context(), req.URL.Host); err != nil {
+		return
+	}
+	return t.next.RoundTrip(req)
+}
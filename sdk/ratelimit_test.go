@@ -0,0 +1,77 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Use a fake clock which is advanced by sleeping
+func fakeClockLimiter(limit RateLimit) *RateLimiter {
+	limiter := NewRateLimiter(limit)
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return ctx.Err()
+	}
+	return limiter
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := fakeClockLimiter(RateLimit{RequestsPerSecond: 2, Burst: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 6; i++ {
+		require.Nil(t, limiter.Wait(ctx, "customerconnect.vmware.com"))
+	}
+
+	// The burst is sent at once, then one request every 500ms
+	stats := limiter.Stats()
+	assert.Equal(t, int64(6), stats.Requests)
+	assert.Equal(t, int64(4), stats.Delayed)
+	assert.Equal(t, 2*time.Second, stats.WaitTime)
+}
+
+func TestRateLimiterPerHost(t *testing.T) {
+	limiter := fakeClockLimiter(RateLimit{RequestsPerSecond: 1, PerHost: true})
+	ctx := context.Background()
+
+	require.Nil(t, limiter.Wait(ctx, "customerconnect.vmware.com"))
+	require.Nil(t, limiter.Wait(ctx, "download3.vmware.com"))
+	assert.Equal(t, int64(0), limiter.Stats().Delayed)
+
+	require.Nil(t, limiter.Wait(ctx, "customerconnect.vmware.com"))
+	assert.Equal(t, int64(1), limiter.Stats().Delayed)
+}
+
+func TestRateLimiterCancelled(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 0.001})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.Nil(t, limiter.Wait(ctx, ""))
+	assert.ErrorIs(t, limiter.Wait(ctx, ""), context.DeadlineExceeded)
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	srv := newFakeServer(t)
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 200})
+	opts := fakeClientOptions(srv)
+	opts.RateLimiter = limiter
+	client := NewClient(opts)
+
+	_, err := client.GetSubProductsMap("vmware_tools", "PRODUCT_BINARY", "")
+	require.Nil(t, err)
+
+	// Every request of the crawl passed through the limiter
+	stats := limiter.Stats()
+	assert.Greater(t, stats.Requests, int64(4))
+	assert.Greater(t, stats.Delayed, int64(0))
+}
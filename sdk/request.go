@@ -0,0 +1,88 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// All requests are built with a context, so callers can cancel calls or enforce deadlines
+
+func (c *Client) get(ctx context.Context, endpoint string) (res *http.Response, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return
+	}
+	return c.do(req)
+}
+
+func (c *Client) post(ctx context.Context, endpoint, contentType string, body io.Reader) (res *http.Response, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.do(req)
+}
+
+// do sends the request, logging in again and retrying once when the session has expired.
+// The X-XSRF-TOKEN header is replaced with the token of the new session on retry.
+func (c *Client) do(req *http.Request) (res *http.Response, err error) {
+	_, generation := c.currentSession()
+	res, err = c.HttpClient.Do(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || c.credentials == nil || c.jar == nil {
+		return
+	}
+	// The body has been consumed and cannot be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return
+	}
+	res.Body.Close()
+
+	if err = c.reauthenticate(req.Context(), generation); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	// The client adds the cookies of the expired session to the request headers
+	retry.Header.Del("Cookie")
+	if retry.Header.Get("X-XSRF-TOKEN") != "" {
+		retry.Header.Set("X-XSRF-TOKEN", c.CurrentXsrfToken())
+	}
+	return c.HttpClient.Do(retry)
+}
+
+func httpGet(ctx context.Context, httpClient *http.Client, endpoint string) (res *http.Response, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return
+	}
+	return httpClient.Do(req)
+}
+
+func httpPost(ctx context.Context, httpClient *http.Client, endpoint, contentType string, body io.Reader) (res *http.Response, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	return httpClient.Do(req)
+}
+
+func httpPostForm(ctx context.Context, httpClient *http.Client, endpoint string, data url.Values) (res *http.Response, err error) {
+	return httpPost(ctx, httpClient, endpoint, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelledContext(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.GetSubProductsMapCtx(ctx, "vmware_tools", "PRODUCT_BINARY", "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, srv.RequestCount("/channel/public/api/v1.0/products/getProductsAtoZ"))
+}
+
+func TestCancelMidCrawl(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel once the crawl has started fetching download group lists
+	requests := 0
+	client.HttpClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests == 3 {
+			cancel()
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	_, err = client.GetVersionMapCtx(ctx, "vmware_tools", "vmtools", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, requests)
+}
+
+func TestDeadlineGenerateDownloadPayload(t *testing.T) {
+	srv := newFakeServer(t)
+	client := fakeLogin(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, err = client.GenerateDownloadPayloadCtx(ctx, "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	payloads, err := client.GenerateDownloadPayloadCtx(context.Background(), "vmware_tools", "vmtools", "11.1.1", "VMware-Tools-*", "PRODUCT_BINARY", true)
+	assert.Nil(t, err)
+	assert.Len(t, payloads, 3)
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with a transient error are retried
+type RetryPolicy struct {
+	// Total number of attempts, including the first. 1 disables retries.
+	MaxAttempts int
+	// Backoff before the first retry, doubled for every following retry with jitter added
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Responses with these status codes are retried. Retry-After is honoured, up to MaxBackoff.
+	RetryableStatusCodes []int
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff before the given retry, starting at 1. Half of the delay is random, so clients
+// which failed at the same time do not retry at the same time.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryTransport retries requests failing with a connection error or a retryable status code
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+	logger *slog.Logger
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(next http.RoundTripper, policy RetryPolicy, logger *slog.Logger) *retryTransport {
+	return &retryTransport{next: next, policy: policy, logger: loggerOrDiscard(logger), sleep: sleepCtx}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return
+			}
+		}
+
+		res, err = t.next.RoundTrip(attemptReq)
+		retry := t.shouldRetry(ctx, res, err)
+
+		// The body cannot be sent again
+		if !retry || attempt >= t.policy.MaxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return
+		}
+
+		delay := t.policy.backoff(attempt)
+		attrs := []any{"method", req.Method, "url", redactURL(req.URL), "attempt", attempt}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		} else {
+			attrs = append(attrs, "status", res.StatusCode)
+		}
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+				if delay > t.policy.MaxBackoff {
+					delay = t.policy.MaxBackoff
+				}
+			}
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		t.logger.DebugContext(ctx, "retrying request", append(attrs, "delay", delay)...)
+		if err = t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// Connection resets, timeouts and other transport errors. Untrusted certificates won't fix themselves.
+		var certErr *tls.CertificateVerificationError
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.As(err, &certErr)
+	}
+	return t.policy.retryableStatus(res.StatusCode)
+}
+
+func rewindRequest(req *http.Request) (rewound *http.Request, err error) {
+	rewound = req.Clone(req.Context())
+	if req.GetBody != nil {
+		rewound.Body, err = req.GetBody()
+	}
+	return
+}
+
+// Retry-After is either a number of seconds or an HTTP date
+func parseRetryAfter(value string) (delay time.Duration, ok bool) {
+	if value == "" {
+		return
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
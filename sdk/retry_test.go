@@ -0,0 +1,129 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func fastRetryPolicy(maxAttempts int) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return &policy
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.RetryPolicy = fastRetryPolicy(3)
+	client := NewClient(opts)
+
+	srv.FailNext(dlgListPath, http.StatusServiceUnavailable, http.StatusBadGateway)
+	_, err := client.GetDlgEditionsList("vmware_tools", "12_x", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Equal(t, 3, srv.RequestCount(dlgListPath))
+
+	// Gives up after MaxAttempts
+	srv.FailNext(dlgListPath, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, err = client.GetDlgEditionsList("vmware_tools", "11_x", "PRODUCT_BINARY")
+	assert.NotNil(t, err)
+	assert.Equal(t, 6, srv.RequestCount(dlgListPath))
+
+	// Other errors are not retried
+	srv.FailNext(dlgListPath, http.StatusNotFound)
+	_, err = client.GetDlgEditionsList("vmware_tools", "10_x", "PRODUCT_BINARY")
+	assert.NotNil(t, err)
+	assert.Equal(t, 7, srv.RequestCount(dlgListPath))
+}
+
+func TestLoginRetriesEmptyBody(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+
+	// Every attempt starts again from the init page
+	u, _ := url.Parse(srv.AuthURL())
+	srv.FailNext(u.Path, http.StatusOK, http.StatusOK)
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 3, authRequestCount(t, srv))
+	assert.Equal(t, 3, srv.RequestCount(initPath))
+
+	// An empty body after the last attempt is not reported as a wrong password
+	srv.FailNext(u.Path, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK)
+	_, err = LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.ErrorIs(t, err, ErrorConnectionFailure)
+	assert.NotErrorIs(t, err, ErrorAuthenticationFailure)
+	assert.Equal(t, 7, authRequestCount(t, srv))
+}
+
+func TestRetryAfter(t *testing.T) {
+	attempts := 0
+	var bodies []string
+	transport := newRetryTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+
+		res := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader("ok"))}
+		switch attempts {
+		case 1:
+			res.StatusCode = http.StatusTooManyRequests
+			res.Header.Set("Retry-After", "2")
+		case 2:
+			res.StatusCode = http.StatusServiceUnavailable
+			res.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		}
+		return res, nil
+	}), DefaultRetryPolicy(), nil)
+
+	var delays []time.Duration
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com", strings.NewReader("payload"))
+	res, err := transport.RoundTrip(req)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	// The date is capped at MaxBackoff
+	assert.Equal(t, []time.Duration{2 * time.Second, 30 * time.Second}, delays)
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+}
+
+func TestRetryCancelled(t *testing.T) {
+	transport := newRetryTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: make(http.Header), Body: http.NoBody}, nil
+	}), DefaultRetryPolicy(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	_, err := transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	for retry, max := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second} {
+		delay := policy.backoff(retry + 1)
+		assert.GreaterOrEqual(t, delay, max/2)
+		assert.LessOrEqual(t, delay, max)
+	}
+	assert.LessOrEqual(t, policy.backoff(20), policy.MaxBackoff)
+}
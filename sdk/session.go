@@ -0,0 +1,185 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Session holds the cookies of a logged in session, so it can be reused by later runs
+type Session struct {
+	Cookies []*http.Cookie `json:"cookies"`
+	SavedAt time.Time      `json:"savedAt"`
+}
+
+// SessionStore persists the session of a client. Login restores a stored session when it is
+// still valid, and saves the session after every login.
+type SessionStore interface {
+	// Load returns ErrorNoSession when nothing has been stored
+	Load(ctx context.Context) (session *Session, err error)
+	Save(ctx context.Context, session *Session) error
+	Clear(ctx context.Context) error
+}
+
+var ErrorNoSession = errors.New("session: no stored session")
+
+// MemorySessionStore keeps the session for the lifetime of the process
+type MemorySessionStore struct {
+	mu      sync.Mutex
+	session *Session
+}
+
+func (m *MemorySessionStore) Load(ctx context.Context) (session *Session, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session == nil {
+		return nil, ErrorNoSession
+	}
+	return m.session, nil
+}
+
+func (m *MemorySessionStore) Save(ctx context.Context, session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.session = session
+	return nil
+}
+
+func (m *MemorySessionStore) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.session = nil
+	return nil
+}
+
+// FileSessionStore stores the session as JSON in a file readable only by the current user.
+// Set ReadOnly when the file is provisioned externally, e.g. a mounted Kubernetes secret,
+// so Save and Clear leave it untouched.
+type FileSessionStore struct {
+	Path     string
+	ReadOnly bool
+}
+
+func (f FileSessionStore) Load(ctx context.Context) (session *Session, err error) {
+	var data []byte
+	data, err = os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrorNoSession
+	} else if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &session)
+	return
+}
+
+func (f FileSessionStore) Save(ctx context.Context, session *Session) (err error) {
+	if f.ReadOnly {
+		return
+	}
+
+	var data []byte
+	if data, err = json.Marshal(session); err != nil {
+		return
+	}
+	return writeFileAtomic(f.Path, data)
+}
+
+func (f FileSessionStore) Clear(ctx context.Context) (err error) {
+	if f.ReadOnly {
+		return
+	}
+
+	err = os.Remove(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+// Write to a temporary file first, so a session is never partially written
+func writeFileAtomic(path string, data []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+
+	var tmp *os.File
+	if tmp, err = os.CreateTemp(dir, filepath.Base(path)+".*"); err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return
+}
+
+// Jars which can remove all of their cookies, such as persistent-cookiejar
+type cookieRemover interface {
+	RemoveAll()
+}
+
+// clearCookies removes the cookies of the Customer Connect hosts from jar
+func clearCookies(jar http.CookieJar, endpoints Endpoints) {
+	if remover, ok := jar.(cookieRemover); ok {
+		remover.RemoveAll()
+		return
+	}
+
+	// Other jars can only be cleared by expiring each cookie
+	for _, rawURL := range []string{endpoints.BaseURL, endpoints.AuthURL} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		var expired []*http.Cookie
+		for _, cookie := range jar.Cookies(u) {
+			expired = append(expired, &http.Cookie{Name: cookie.Name, Path: "/", MaxAge: -1})
+		}
+		jar.SetCookies(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}, expired)
+	}
+}
+
+func hasCookies(jar http.CookieJar, endpoints Endpoints) bool {
+	u, err := url.Parse(endpoints.BaseURL)
+	return err == nil && len(jar.Cookies(u)) > 0
+}
+
+func sessionFromJar(jar http.CookieJar, endpoints Endpoints) (session *Session, err error) {
+	var u *url.URL
+	if u, err = url.Parse(endpoints.BaseURL); err != nil {
+		return
+	}
+	session = &Session{Cookies: jar.Cookies(u), SavedAt: time.Now()}
+	return
+}
+
+func restoreSession(jar http.CookieJar, endpoints Endpoints, session *Session) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(endpoints.BaseURL); err != nil {
+		return
+	}
+
+	cookies := make([]*http.Cookie, 0, len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+	}
+	jar.SetCookies(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}, cookies)
+	return
+}
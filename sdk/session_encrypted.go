@@ -0,0 +1,254 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	DefaultSessionKeyEnv = "VMWCC_SESSION_KEY"
+
+	encryptedSessionVersion = 1
+	encryptedSessionKDF     = "scrypt"
+)
+
+// scrypt parameters for new files. The parameters are stored with each file, so they can be raised
+// without breaking existing sessions.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+var (
+	ErrorMissingSessionKey  = errors.New("session: no passphrase for the encrypted session")
+	ErrorSessionDecrypt     = errors.New("session: could not decrypt session, the passphrase may be wrong")
+	ErrorSessionFileVersion = errors.New("session: unsupported session file version")
+)
+
+// EncryptedFileSessionStore stores the session in a file encrypted with AES-GCM, using a key derived
+// from a passphrase with scrypt.
+type EncryptedFileSessionStore struct {
+	Path string
+	// Passphrase used to derive the key. When empty the passphrase is read from PassphraseEnv.
+	Passphrase string
+	// Defaults to VMWCC_SESSION_KEY
+	PassphraseEnv string
+
+	// MigrateFrom is a plaintext persistent-cookiejar file, e.g. ~/.vmware.cookies. When Path does not
+	// exist yet, the cookies for Host are encrypted into Path and the plaintext file is removed.
+	MigrateFrom string
+	// Defaults to the host of DefaultBaseURL
+	Host string
+}
+
+// The versioned on-disk format. []byte fields are base64 encoded by encoding/json.
+type encryptedSessionFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (e EncryptedFileSessionStore) passphrase() (passphrase string, err error) {
+	passphrase = e.Passphrase
+	if passphrase == "" {
+		env := e.PassphraseEnv
+		if env == "" {
+			env = DefaultSessionKeyEnv
+		}
+		if passphrase = os.Getenv(env); passphrase == "" {
+			err = fmt.Errorf("%w: set %s", ErrorMissingSessionKey, env)
+		}
+	}
+	return
+}
+
+func (e EncryptedFileSessionStore) Load(ctx context.Context) (session *Session, err error) {
+	var data []byte
+	data, err = os.ReadFile(e.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		if e.MigrateFrom == "" {
+			return nil, ErrorNoSession
+		}
+		return e.migrate(ctx)
+	} else if err != nil {
+		return
+	}
+
+	var file encryptedSessionFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return
+	}
+	if file.Version != encryptedSessionVersion || file.KDF != encryptedSessionKDF {
+		err = fmt.Errorf("%w: version %d, kdf %q", ErrorSessionFileVersion, file.Version, file.KDF)
+		return
+	}
+
+	var passphrase string
+	if passphrase, err = e.passphrase(); err != nil {
+		return
+	}
+
+	var gcm cipher.AEAD
+	if gcm, err = sessionCipher(passphrase, file.Salt, file.N, file.R, file.P); err != nil {
+		return
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		err = ErrorSessionDecrypt
+		return
+	}
+
+	var plaintext []byte
+	if plaintext, err = gcm.Open(nil, file.Nonce, file.Ciphertext, sessionAdditionalData(file)); err != nil {
+		err = ErrorSessionDecrypt
+		return
+	}
+
+	err = json.Unmarshal(plaintext, &session)
+	return
+}
+
+func (e EncryptedFileSessionStore) Save(ctx context.Context, session *Session) (err error) {
+	var passphrase string
+	if passphrase, err = e.passphrase(); err != nil {
+		return
+	}
+
+	var plaintext []byte
+	if plaintext, err = json.Marshal(session); err != nil {
+		return
+	}
+
+	file := encryptedSessionFile{
+		Version: encryptedSessionVersion,
+		KDF:     encryptedSessionKDF,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saltLen),
+	}
+	if _, err = rand.Read(file.Salt); err != nil {
+		return
+	}
+
+	var gcm cipher.AEAD
+	if gcm, err = sessionCipher(passphrase, file.Salt, file.N, file.R, file.P); err != nil {
+		return
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(file.Nonce); err != nil {
+		return
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, sessionAdditionalData(file))
+
+	var data []byte
+	if data, err = json.Marshal(file); err != nil {
+		return
+	}
+	return writeFileAtomic(e.Path, data)
+}
+
+func (e EncryptedFileSessionStore) Clear(ctx context.Context) (err error) {
+	err = os.Remove(e.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+func sessionCipher(passphrase string, salt []byte, n, r, p int) (gcm cipher.AEAD, err error) {
+	var key []byte
+	if key, err = scrypt.Key([]byte(passphrase), salt, n, r, p, scryptKeyLen); err != nil {
+		return
+	}
+
+	var block cipher.Block
+	if block, err = aes.NewCipher(key); err != nil {
+		return
+	}
+	return cipher.NewGCM(block)
+}
+
+// Authenticate the header, so the version and KDF parameters cannot be altered
+func sessionAdditionalData(file encryptedSessionFile) []byte {
+	return []byte(fmt.Sprintf("%d/%s/%d/%d/%d", file.Version, file.KDF, file.N, file.R, file.P))
+}
+
+// The subset of a persistent-cookiejar entry needed to restore a session
+type plaintextCookie struct {
+	Name    string
+	Value   string
+	Domain  string
+	Path    string
+	Expires time.Time
+}
+
+// migrate encrypts the session from a plaintext persistent-cookiejar file. The plaintext file is only
+// removed once the encrypted file has been written and read back.
+func (e EncryptedFileSessionStore) migrate(ctx context.Context) (session *Session, err error) {
+	var data []byte
+	data, err = os.ReadFile(e.MigrateFrom)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrorNoSession
+	} else if err != nil {
+		return
+	}
+
+	var entries []plaintextCookie
+	if err = json.Unmarshal(data, &entries); err != nil {
+		err = fmt.Errorf("session: could not read %s: %w", e.MigrateFrom, err)
+		return
+	}
+
+	host := e.Host
+	if host == "" {
+		host = defaultHost()
+	}
+
+	session = &Session{SavedAt: time.Now()}
+	for _, entry := range entries {
+		expired := !entry.Expires.IsZero() && entry.Expires.Before(session.SavedAt)
+		if entry.Value == "" || expired || !cookieDomainMatch(host, entry.Domain) {
+			continue
+		}
+		session.Cookies = append(session.Cookies, &http.Cookie{Name: entry.Name, Value: entry.Value, Path: entry.Path})
+	}
+	if len(session.Cookies) == 0 {
+		return nil, ErrorNoSession
+	}
+
+	if err = e.Save(ctx, session); err != nil {
+		return
+	}
+	if _, err = e.Load(ctx); err != nil {
+		return
+	}
+	err = os.Remove(e.MigrateFrom)
+	return
+}
+
+func cookieDomainMatch(host, domain string) bool {
+	domain = strings.TrimPrefix(domain, ".")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
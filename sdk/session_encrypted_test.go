@@ -0,0 +1,148 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/persistent-cookiejar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func TestEncryptedFileSessionStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session")
+	store := EncryptedFileSessionStore{Path: path, Passphrase: "correct horse"}
+
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, ErrorNoSession)
+
+	session := &Session{Cookies: []*http.Cookie{{Name: "JSESSIONID", Value: "session-secret"}}}
+	require.Nil(t, store.Save(ctx, session))
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(data), "session-secret")
+	// Windows does not have Unix permission bits
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	loaded, err := store.Load(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, "session-secret", loaded.Cookies[0].Value)
+
+	_, err = EncryptedFileSessionStore{Path: path, Passphrase: "wrong"}.Load(ctx)
+	assert.ErrorIs(t, err, ErrorSessionDecrypt)
+
+	t.Setenv("TEST_VCC_SESSION_KEY", "")
+	_, err = EncryptedFileSessionStore{Path: path, PassphraseEnv: "TEST_VCC_SESSION_KEY"}.Load(ctx)
+	assert.ErrorIs(t, err, ErrorMissingSessionKey)
+
+	t.Setenv("TEST_VCC_SESSION_KEY", "correct horse")
+	_, err = EncryptedFileSessionStore{Path: path, PassphraseEnv: "TEST_VCC_SESSION_KEY"}.Load(ctx)
+	assert.Nil(t, err)
+
+	require.Nil(t, store.Clear(ctx))
+	_, err = store.Load(ctx)
+	assert.ErrorIs(t, err, ErrorNoSession)
+}
+
+func TestEncryptedFileSessionStoreVersion(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session")
+	store := EncryptedFileSessionStore{Path: path, Passphrase: "correct horse"}
+	require.Nil(t, store.Save(ctx, &Session{}))
+
+	var file map[string]interface{}
+	data, _ := os.ReadFile(path)
+	require.Nil(t, json.Unmarshal(data, &file))
+	file["version"] = 2
+	data, _ = json.Marshal(file)
+	require.Nil(t, os.WriteFile(path, data, 0600))
+
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, ErrorSessionFileVersion)
+}
+
+func TestEncryptedFileSessionStoreMigration(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	plaintextPath := filepath.Join(dir, ".vmware.cookies")
+
+	jar, err := cookiejar.New(&cookiejar.Options{Filename: plaintextPath, PersistSessionCookies: true})
+	require.Nil(t, err)
+	u, _ := url.Parse(DefaultBaseURL)
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "JSESSIONID", Value: "session-secret", Path: "/", Expires: time.Now().Add(time.Hour)},
+		{Name: "XSRF-TOKEN", Value: "xsrf", Path: "/"},
+	})
+	other, _ := url.Parse("https://example.com")
+	jar.SetCookies(other, []*http.Cookie{{Name: "other", Value: "other", Path: "/"}})
+	require.Nil(t, jar.Save())
+
+	store := EncryptedFileSessionStore{
+		Path:        filepath.Join(dir, ".vmware.session"),
+		Passphrase:  "correct horse",
+		MigrateFrom: plaintextPath,
+	}
+	session, err := store.Load(ctx)
+	require.Nil(t, err)
+	require.Len(t, session.Cookies, 2)
+
+	_, err = os.Stat(plaintextPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Later loads read the encrypted file
+	session, err = store.Load(ctx)
+	require.Nil(t, err)
+	assert.Len(t, session.Cookies, 2)
+}
+
+func TestLoginEncryptedSessionStore(t *testing.T) {
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = EncryptedFileSessionStore{Path: filepath.Join(t.TempDir(), "session"), Passphrase: fakecc.Password}
+
+	_, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 1, authRequestCount(t, srv))
+}
+
+func TestLoginEncryptedSessionStoreRotatedKey(t *testing.T) {
+	srv := newFakeServer(t)
+	path := filepath.Join(t.TempDir(), "session")
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = EncryptedFileSessionStore{Path: path, Passphrase: "old key"}
+	_, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+
+	// A session encrypted with another key is replaced by a fresh login
+	rotated := EncryptedFileSessionStore{Path: path, Passphrase: "new key"}
+	opts.SessionStore = rotated
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 2, authRequestCount(t, srv))
+
+	_, err = rotated.Load(context.Background())
+	assert.Nil(t, err)
+}
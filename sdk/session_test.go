@@ -0,0 +1,134 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func authRequestCount(t *testing.T, srv *fakecc.Server) int {
+	u, err := url.Parse(srv.AuthURL())
+	require.Nil(t, err)
+	return srv.RequestCount(u.Path)
+}
+
+func TestLoginStdlibJar(t *testing.T) {
+	srv := newFakeServer(t)
+	jar, _ := cookiejar.New(nil)
+
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, jar, fakeClientOptions(srv))
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+
+	// The expired cookies are removed from the jar before logging in again
+	srv.ExpireSessions()
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 2, authRequestCount(t, srv))
+}
+
+func TestLoginNilJar(t *testing.T) {
+	srv := newFakeServer(t)
+
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, fakeClientOptions(srv))
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+}
+
+func TestLoginSessionStore(t *testing.T) {
+	srv := newFakeServer(t)
+	store := &MemorySessionStore{}
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = store
+
+	_, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Equal(t, 1, authRequestCount(t, srv))
+
+	session, err := store.Load(context.Background())
+	require.Nil(t, err)
+	assert.NotEmpty(t, session.Cookies)
+
+	// A valid stored session is reused without logging in
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 1, authRequestCount(t, srv))
+
+	// An expired session is replaced
+	srv.ExpireSessions()
+	client, err = LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+	assert.Equal(t, 2, authRequestCount(t, srv))
+
+	refreshed, err := store.Load(context.Background())
+	require.Nil(t, err)
+	assert.NotEqual(t, session.Cookies, refreshed.Cookies)
+}
+
+func TestFileSessionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vcc", "session.json")
+	store := FileSessionStore{Path: path}
+	ctx := context.Background()
+
+	_, err := store.Load(ctx)
+	assert.ErrorIs(t, err, ErrorNoSession)
+
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = store
+	_, err = LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+
+	// Windows does not have Unix permission bits
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	session, err := store.Load(ctx)
+	require.Nil(t, err)
+	assert.NotEmpty(t, session.Cookies)
+
+	// Read only stores are never modified
+	readOnly := FileSessionStore{Path: path, ReadOnly: true}
+	assert.Nil(t, readOnly.Save(ctx, &Session{}))
+	assert.Nil(t, readOnly.Clear(ctx))
+	unchanged, err := readOnly.Load(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, session.Cookies, unchanged.Cookies)
+
+	require.Nil(t, store.Clear(ctx))
+	_, err = store.Load(ctx)
+	assert.ErrorIs(t, err, ErrorNoSession)
+}
+
+func TestLoginCorruptSessionFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(path, []byte("garbage"), 0600))
+
+	srv := newFakeServer(t)
+	opts := fakeClientOptions(srv)
+	opts.SessionStore = FileSessionStore{Path: path}
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	assert.Nil(t, client.CheckLoggedIn())
+
+	// The corrupt file is replaced by the new session
+	session, err := opts.SessionStore.Load(context.Background())
+	require.Nil(t, err)
+	assert.NotEmpty(t, session.Cookies)
+}
@@ -4,13 +4,17 @@
 package sdk
 
 import (
+	"net/http"
 	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetSubProductsSlice(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProducts []SubProductDetails
 	subProducts, err = basicClient.GetSubProductsSlice("vmware_horizon", "PRODUCT_BINARY", "")
 	assert.Nil(t, err)
@@ -18,6 +22,8 @@ func TestGetSubProductsSlice(t *testing.T) {
 }
 
 func TestGetSubProductsSliceDrivers(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProducts []SubProductDetails
 	subProducts, err = basicClient.GetSubProductsSlice("vmware_vsphere", "DRIVERS_TOOLS", "")
 	assert.Nil(t, err)
@@ -31,13 +37,16 @@ func TestGetSubProductsSliceDrivers(t *testing.T) {
 }
 
 func TestGetSubProductsSliceInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var subProducts []SubProductDetails
-	subProducts, err = basicClient.GetSubProductsSlice("vsphere", "PRODUCT_BINARY", "")
+	subProducts, err = client.GetSubProductsSlice("vsphere", "PRODUCT_BINARY", "")
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, subProducts, "Expected response to be empty")
 }
 
 func TestGetSubProductNsxLE(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProduct SubProductDetails
 	subProduct, err = basicClient.GetSubProduct("vmware_nsx_t_data_center", "nsx-t_le", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -46,6 +55,8 @@ func TestGetSubProductNsxLE(t *testing.T) {
 }
 
 func TestGetSubProductDriver(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProduct SubProductDetails
 	subProduct, err = basicClient.GetSubProduct("vmware_horizon", "dem+standard", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -53,13 +64,16 @@ func TestGetSubProductDriver(t *testing.T) {
 }
 
 func TestGetSubProductsMap(t *testing.T) {
+	client := newFakeClient(t)
 	var subProducts map[string]SubProductDetails
-	subProducts, err = basicClient.GetSubProductsMap("vmware_vsphere", "PRODUCT_BINARY", "")
+	subProducts, err = client.GetSubProductsMap("vmware_vsphere", "PRODUCT_BINARY", "")
 	assert.Nil(t, err)
-	assert.Contains(t, subProducts, "vmtools")
+	assert.Contains(t, subProducts, "esxi")
 }
 
 func TestGetSubProductsMapHorizon(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProducts map[string]SubProductDetails
 	subProducts, err = basicClient.GetSubProductsMap("vmware_horizon_clients", "PRODUCT_BINARY", "")
 	assert.Nil(t, err)
@@ -69,6 +83,8 @@ func TestGetSubProductsMapHorizon(t *testing.T) {
 }
 
 func TestGetSubProductsMapNsxLe(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProducts map[string]SubProductDetails
 	subProducts, err = basicClient.GetSubProductsMap("vmware_nsx", "PRODUCT_BINARY", "")
 	assert.Nil(t, err)
@@ -77,6 +93,8 @@ func TestGetSubProductsMapNsxLe(t *testing.T) {
 }
 
 func TestGetSubProductsMapNsxTLe(t *testing.T) {
+	skipUnlessLive(t)
+
 	var subProducts map[string]SubProductDetails
 	subProducts, err = basicClient.GetSubProductsMap("vmware_nsx_t_data_center", "PRODUCT_BINARY", "")
 	assert.Nil(t, err)
@@ -85,30 +103,34 @@ func TestGetSubProductsMapNsxTLe(t *testing.T) {
 }
 
 func TestGetSubProductsMapInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var subProductMap map[string]SubProductDetails
-	subProductMap, err = basicClient.GetSubProductsMap("vsphere", "PRODUCT_BINARY", "" )
+	subProductMap, err = client.GetSubProductsMap("vsphere", "PRODUCT_BINARY", "" )
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, subProductMap, "Expected response to be empty")
 }
 
 func TestGetSubProductsDetails(t *testing.T) {
+	client := newFakeClient(t)
 	var subProductDetails DlgList
-	subProductDetails, err = basicClient.GetSubProductDetails("vmware_vsphere", "vmtools", "6_7", "PRODUCT_BINARY")
+	subProductDetails, err = client.GetSubProductDetails("vmware_vsphere", "esxi", "8_0", "PRODUCT_BINARY")
 	assert.Nil(t, err)
 	assert.NotEmpty(t, subProductDetails.Code, "Expected response to not be empty")
 }
 
 func TestGetSubProductsDetailsInvalidSubProduct(t *testing.T) {
+	client := newFakeClient(t)
 	var subProductDetails DlgList
-	subProductDetails, err = basicClient.GetSubProductDetails("vmware_vsphere", "tools", "6_7", "PRODUCT_BINARY")
+	subProductDetails, err = client.GetSubProductDetails("vmware_vsphere", "tools", "8_0", "PRODUCT_BINARY")
 	assert.NotNil(t, err)
 	assert.ErrorIs(t, err, ErrorInvalidSubProduct)
 	assert.Empty(t, subProductDetails.Code, "Expected response to be empty")
 }
 
 func TestGetSubProductsDetailsInvalidMajorVersion(t *testing.T) {
+	client := newFakeClient(t)
 	var subProductDetails DlgList
-	subProductDetails, err = basicClient.GetSubProductDetails("vmware_vsphere", "vmtools", "5_5", "PRODUCT_BINARY")
+	subProductDetails, err = client.GetSubProductDetails("vmware_vsphere", "esxi", "5_5", "PRODUCT_BINARY")
 	assert.NotNil(t, err)
 	assert.ErrorIs(t, err, ErrorInvalidSubProductMajorVersion)
 	assert.Empty(t, subProductDetails.Code, "Expected response to be empty")
@@ -156,4 +178,15 @@ func TestGetProductCode(t *testing.T) {
 	productCode = "OEM-ESXI70U3-HPE"
 	productCode = getProductCode(productCode, "vmware_vsphere", "ADDONS", reEndVersion)
 	assert.Equal(t, "oem-esxi70u3-hpe", productCode)
-}
\ No newline at end of file
+}
+
+func TestGetSubProductsMapSkipsBrokenMajorVersion(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	// One major version failing does not hide the sub-products of the others
+	srv.FailNext(dlgListPath, http.StatusNotFound)
+	subProducts, err := client.GetSubProductsMap("vmware_tools", "PRODUCT_BINARY", "")
+	require.Nil(t, err)
+	assert.Contains(t, subProducts, "vmtools")
+}
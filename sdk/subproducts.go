@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"errors"
 	"regexp"
 	"slices"
@@ -26,16 +27,20 @@ var ErrorInvalidSubProduct = errors.New("subproduct: invalid subproduct requeste
 var ErrorInvalidSubProductMajorVersion = errors.New("subproduct: invalid major version requested")
 
 func (c *Client) GetSubProductsMap(slug, dlgType, requestedMajorVersion string) (subProductMap map[string]SubProductDetails, err error) {
-	c.EnsureProductDetailMap()
-	if err != nil {
+	return c.GetSubProductsMapCtx(context.Background(), slug, dlgType, requestedMajorVersion)
+}
+
+func (c *Client) GetSubProductsMapCtx(ctx context.Context, slug, dlgType, requestedMajorVersion string) (subProductMap map[string]SubProductDetails, err error) {
+	ctx, span := c.startSpan(ctx, "GetSubProductsMap", attr(AttrSlug, slug), attr(AttrDlgType, dlgType), attr(AttrMajorVersion, requestedMajorVersion))
+	defer func() { endSpan(span, err) }()
+	if _, err = c.lookupProduct(ctx, slug); err != nil {
 		return
 	}
-	if _, ok := ProductDetailMap[slug]; !ok {
-		err = ErrorInvalidSlug
-		return
+	if c.snapshot != nil {
+		return c.snapshot.subProducts(slug, dlgType, requestedMajorVersion)
 	}
 	var majorVersions []string
-	majorVersions, err = c.GetMajorVersionsSlice(slug)
+	majorVersions, err = c.GetMajorVersionsSliceCtx(ctx, slug)
 	if err != nil {
 		return
 	}
@@ -48,7 +53,7 @@ func (c *Client) GetSubProductsMap(slug, dlgType, requestedMajorVersion string)
 			err = ErrorInvalidSubProductMajorVersion
 			return
 		}
-		err = c.processMajorVersion(slug, requestedMajorVersion, dlgType, subProductMap)
+		err = c.processMajorVersion(ctx, slug, requestedMajorVersion, dlgType, subProductMap)
 		if err != nil {
 			return
 		}
@@ -56,21 +61,21 @@ func (c *Client) GetSubProductsMap(slug, dlgType, requestedMajorVersion string)
 			// Iterate major product versions and extract all unique products
 			// All version information is stripped
 		for _, majorVersion := range majorVersions {		
-			c.processMajorVersion(slug, majorVersion, dlgType, subProductMap)
-			// Invalid version errors need to be ignored, as they come from deprecated products
-			if err == ErrorInvalidVersion {
-				err = nil
-			} else if err != nil {
-				return
+			// Errors need to be ignored, as they come from deprecated products, unless the request was cancelled
+			if processErr := c.processMajorVersion(ctx, slug, majorVersion, dlgType, subProductMap); processErr != nil {
+				if err = ctx.Err(); err != nil {
+					return
+				}
+				c.log().DebugContext(ctx, "skipping major version", "slug", slug, "majorVersion", majorVersion, "error", processErr)
 			}
 		}
 	}
 	return
 }
 
-func (c *Client) processMajorVersion (slug, majorVersion, dlgType string, subProductMap map[string]SubProductDetails) (err error) {
+func (c *Client) processMajorVersion (ctx context.Context, slug, majorVersion, dlgType string, subProductMap map[string]SubProductDetails) (err error) {
 	var dlgEditionsList []DlgEditionsLists
-		dlgEditionsList, err = c.GetDlgEditionsList(slug, majorVersion, dlgType)
+		dlgEditionsList, err = c.GetDlgEditionsListCtx(ctx, slug, majorVersion, dlgType)
 		if err != nil {
 			return
 		}
@@ -82,6 +87,8 @@ func (c *Client) processMajorVersion (slug, majorVersion, dlgType string, subPro
 				
 				productName := getProductName(dlgList.Name, slug, dlgType, reEndVersion)
 				productCode := getProductCode(strings.ToLower(dlgList.Code), slug, dlgType, reEndVersion)
+				c.log().DebugContext(ctx, "normalized sub-product", "slug", slug, "majorVersion", majorVersion,
+					"code", dlgList.Code, "subProduct", productCode, "name", dlgList.Name, "subProductName", productName)
 
 				// Initalize the struct for a product code for the first time
 				if _, ok := subProductMap[productCode]; !ok {
@@ -95,6 +102,7 @@ func (c *Client) processMajorVersion (slug, majorVersion, dlgType string, subPro
 				subProductMap[productCode].DlgListByVersion[majorVersion] = dlgList
 
 				if productCode == "nsx" || productCode == "nsx-t" {
+					c.log().DebugContext(ctx, "adding limited edition sub-product", "subProduct", productCode+"_le")
 					duplicateNsxToNsxLe(subProductMap, productCode, productName, majorVersion, dlgList)
 				}
 			}
@@ -179,7 +187,13 @@ func duplicateNsxToNsxLe(subProductMap map[string]SubProductDetails, productCode
 }
 
 func (c *Client) GetSubProductsSlice(slug, dlgType, majorVersion string) (data []SubProductDetails, err error) {
-	subProductMap, err := c.GetSubProductsMap(slug, dlgType, majorVersion)
+	return c.GetSubProductsSliceCtx(context.Background(), slug, dlgType, majorVersion)
+}
+
+func (c *Client) GetSubProductsSliceCtx(ctx context.Context, slug, dlgType, majorVersion string) (data []SubProductDetails, err error) {
+	ctx, span := c.startSpan(ctx, "GetSubProductsSlice", attr(AttrSlug, slug), attr(AttrDlgType, dlgType), attr(AttrMajorVersion, majorVersion))
+	defer func() { endSpan(span, err) }()
+	subProductMap, err := c.GetSubProductsMapCtx(ctx, slug, dlgType, majorVersion)
 	if err != nil {
 		return
 	}
@@ -202,8 +216,14 @@ func (c *Client) GetSubProductsSlice(slug, dlgType, majorVersion string) (data [
 }
 
 func (c *Client) GetSubProduct(slug, subProduct, dlgType string) (data SubProductDetails, err error) {
+	return c.GetSubProductCtx(context.Background(), slug, subProduct, dlgType)
+}
+
+func (c *Client) GetSubProductCtx(ctx context.Context, slug, subProduct, dlgType string) (data SubProductDetails, err error) {
+	ctx, span := c.startSpan(ctx, "GetSubProduct", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var subProductMap map[string]SubProductDetails
-	subProductMap, err = c.GetSubProductsMap(slug, dlgType, "")
+	subProductMap, err = c.GetSubProductsMapCtx(ctx, slug, dlgType, "")
 	if err != nil {
 		return
 	}
@@ -218,8 +238,14 @@ func (c *Client) GetSubProduct(slug, subProduct, dlgType string) (data SubProduc
 }
 
 func (c *Client) GetSubProductDetails(slug, subProduct, majorVersion, dlgType string) (data DlgList, err error) {
+	return c.GetSubProductDetailsCtx(context.Background(), slug, subProduct, majorVersion, dlgType)
+}
+
+func (c *Client) GetSubProductDetailsCtx(ctx context.Context, slug, subProduct, majorVersion, dlgType string) (data DlgList, err error) {
+	ctx, span := c.startSpan(ctx, "GetSubProductDetails", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrMajorVersion, majorVersion), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var subProducts map[string]SubProductDetails
-	subProducts, err = c.GetSubProductsMap(slug, dlgType, "")
+	subProducts, err = c.GetSubProductsMapCtx(ctx, slug, dlgType, "")
 	if err != nil {
 		return
 	}
@@ -0,0 +1,105 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+// Package tracetest provides an in-memory sdk.Tracer, so tests can assert on the spans produced by a client.
+//
+//	recorder := tracetest.NewRecorder()
+//	client := sdk.NewClient(sdk.ClientOptions{Tracer: recorder})
+//	...
+//	spans := recorder.Spans()
+package tracetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+)
+
+// Span is a recorded span. Fields are only safe to read once the span has ended.
+type Span struct {
+	Name       string
+	Parent     *Span
+	Attributes map[string]interface{}
+	Errors     []error
+	Ended      bool
+
+	recorder *Recorder
+}
+
+func (s *Span) SetAttributes(attrs ...sdk.Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, attr := range attrs {
+		s.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *Span) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+func (s *Span) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Ended = true
+}
+
+// Recorder is a sdk.Tracer which keeps every span in memory
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type spanKey struct{}
+
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...sdk.Attribute) (context.Context, sdk.Span) {
+	span := &Span{
+		Name:       name,
+		Attributes: make(map[string]interface{}),
+		recorder:   r,
+	}
+	span.Parent, _ = ctx.Value(spanKey{}).(*Span)
+	for _, attr := range attrs {
+		span.Attributes[attr.Key] = attr.Value
+	}
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Spans returns all spans in the order they were started
+func (r *Recorder) Spans() []*Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Span(nil), r.spans...)
+}
+
+// Find returns the spans with the given name
+func (r *Recorder) Find(name string) (spans []*Span) {
+	for _, span := range r.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return
+}
+
+// Children returns the spans started directly under parent
+func (r *Recorder) Children(parent *Span) (spans []*Span) {
+	for _, span := range r.Spans() {
+		if span.Parent == parent {
+			spans = append(spans, span)
+		}
+	}
+	return
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package tracetest_test
+
+import (
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk"
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/tracetest"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	recorder := tracetest.NewRecorder()
+	opts := sdk.ClientOptions{
+		Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL()),
+		Tracer:    recorder,
+	}
+	jar, _ := cookiejar.New(nil)
+	client, err := sdk.LoginWithOptions(fakecc.Username, fakecc.Password, jar, opts)
+	require.Nil(t, err)
+
+	_, err = client.GetVersionMap("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	require.Nil(t, err)
+
+	spans := recorder.Find("Client.GetVersionMap")
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.True(t, span.Ended)
+	assert.Nil(t, span.Parent)
+	assert.Equal(t, "vmware_tools", span.Attributes[sdk.AttrSlug])
+	assert.Equal(t, "vmtools", span.Attributes[sdk.AttrSubProduct])
+	assert.Equal(t, "PRODUCT_BINARY", span.Attributes[sdk.AttrDlgType])
+
+	// Nested method calls and HTTP requests are children of the span
+	children := recorder.Children(span)
+	require.NotEmpty(t, children)
+	assert.Equal(t, "Client.GetSubProduct", children[0].Name)
+
+	headers := recorder.Find("Client.GetDlgHeader")
+	require.NotEmpty(t, headers)
+	requests := recorder.Children(headers[0])
+	require.Len(t, requests, 1)
+	assert.Equal(t, "HTTP GET", requests[0].Name)
+	assert.Equal(t, 200, requests[0].Attributes[sdk.AttrHTTPStatusCode])
+	assert.Contains(t, requests[0].Attributes[sdk.AttrHTTPURL], "downloadGroup=")
+}
+
+func TestRecorderError(t *testing.T) {
+	srv := fakecc.NewServer(nil)
+	defer srv.Close()
+
+	recorder := tracetest.NewRecorder()
+	client := sdk.NewClient(sdk.ClientOptions{
+		Endpoints: sdk.NewEndpoints(srv.URL, srv.AuthURL()),
+		Tracer:    recorder,
+	})
+
+	_, err := client.GetVersionMap("vmware_tools", "unknown", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, sdk.ErrorInvalidSubProduct)
+
+	spans := recorder.Find("Client.GetVersionMap")
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Errors, 1)
+	assert.ErrorIs(t, spans[0].Errors[0], sdk.ErrorInvalidSubProduct)
+}
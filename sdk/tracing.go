@@ -0,0 +1,104 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts spans for public Client methods and the HTTP requests they make. It is small enough
+// to be implemented on top of OpenTelemetry or any other tracing library, so the SDK does not depend
+// on one. See the sdk/tracetest package for an in-memory implementation.
+type Tracer interface {
+	// Start returns a span which is a child of any span in ctx, along with a context holding it
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key value pair describing a span. Values are strings or ints.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attribute keys set by the SDK
+const (
+	AttrSlug          = "vcc.slug"
+	AttrSubProduct    = "vcc.subproduct"
+	AttrMajorVersion  = "vcc.major_version"
+	AttrVersion       = "vcc.version"
+	AttrDlgType       = "vcc.dlg_type"
+	AttrDownloadGroup = "vcc.download_group"
+	AttrProductID     = "vcc.product_id"
+	AttrFileName      = "vcc.file_name"
+	AttrFileCount     = "vcc.file_count"
+
+	AttrHTTPMethod     = "http.method"
+	AttrHTTPURL        = "http.url"
+	AttrHTTPStatusCode = "http.status_code"
+)
+
+func attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+func tracerOrNoop(tracer Tracer) Tracer {
+	if tracer == nil {
+		return noopTracer{}
+	}
+	return tracer
+}
+
+// startSpan starts a span for a public method. End it with endSpan, so errors are recorded:
+//
+//	ctx, span := c.startSpan(ctx, "GetVersionMap", attr(AttrSlug, slug))
+//	defer func() { endSpan(span, err) }()
+func (c *Client) startSpan(ctx context.Context, method string, attrs ...Attribute) (context.Context, Span) {
+	return tracerOrNoop(c.tracer).Start(ctx, "Client."+method, attrs...)
+}
+
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// tracingTransport starts a child span for every request sent, including each retry
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer Tracer
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method,
+		attr(AttrHTTPMethod, req.Method),
+		attr(AttrHTTPURL, redactURL(req.URL)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	res, err = t.next.RoundTrip(req.WithContext(ctx))
+	if err == nil {
+		span.SetAttributes(attr(AttrHTTPStatusCode, res.StatusCode))
+	}
+	return
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+var (
+	ErrorInvalidProxy      = errors.New("transport: invalid proxy url")
+	ErrorInvalidCABundle   = errors.New("transport: no certificates found in ca bundle")
+	ErrorIncompleteCertKey = errors.New("transport: client certificate and key must both be set")
+)
+
+// TransportOptions configures the transport built by NewTransport, for networks where
+// requests go through a proxy or TLS is inspected by a corporate CA.
+type TransportOptions struct {
+	// ProxyURL is used for all requests, e.g. http://proxy.example.com:3128.
+	// Defaults to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// CAFile is a PEM bundle trusted in addition to the system roots
+	CAFile string
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key presented for mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// NewTransport returns a copy of http.DefaultTransport with the proxy and TLS settings in opts.
+// Pass it as ClientOptions.Transport.
+func NewTransport(opts TransportOptions) (transport *http.Transport, err error) {
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.ProxyURL != "" {
+		var proxy *url.URL
+		proxy, err = url.Parse(opts.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("%w: %q", ErrorInvalidProxy, opts.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CAFile == "" && opts.ClientCertFile == "" && opts.ClientKeyFile == "" {
+		return
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CAFile != "" {
+		if tlsConfig.RootCAs, err = loadCABundle(opts.CAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, ErrorIncompleteCertKey
+		}
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("transport: loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return
+}
+
+// Load a PEM bundle on top of the system roots
+func loadCABundle(path string) (pool *x509.CertPool, err error) {
+	var pem []byte
+	if pem, err = os.ReadFile(path); err != nil {
+		return
+	}
+
+	pool, err = x509.SystemCertPool()
+	if err != nil {
+		// Not available on every platform, the bundle is then the only source of roots
+		pool, err = x509.NewCertPool(), nil
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		err = fmt.Errorf("%w: %s", ErrorInvalidCABundle, path)
+	}
+	return
+}
@@ -0,0 +1,177 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-labs/vmware-customer-connect-sdk/sdk/fakecc"
+)
+
+func newFakeTLSServer(t *testing.T) (srv *fakecc.Server, caFile string) {
+	t.Helper()
+
+	srv = fakecc.NewTLSServer(nil)
+	t.Cleanup(srv.Close)
+
+	caFile = filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.Nil(t, os.WriteFile(caFile, cert, 0600))
+	return
+}
+
+// writeClientCert creates a self-signed client certificate and returns the certificate, and the paths of the PEM files
+func writeClientCert(t *testing.T) (cert *x509.Certificate, certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vcc-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return
+}
+
+func TestLoginWithCABundle(t *testing.T) {
+	srv, caFile := newFakeTLSServer(t)
+
+	transport, err := NewTransport(TransportOptions{CAFile: caFile})
+	require.Nil(t, err)
+
+	opts := fakeClientOptions(srv)
+	opts.Transport = transport
+	require.Nil(t, CheckConnectivityCtx(context.Background(), opts))
+
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	_, err = client.AccountInfo()
+	assert.Nil(t, err)
+}
+
+func TestLoginUntrustedCertificate(t *testing.T) {
+	srv, _ := newFakeTLSServer(t)
+
+	err := CheckConnectivityCtx(context.Background(), fakeClientOptions(srv))
+	var unknownAuthority x509.UnknownAuthorityError
+	assert.ErrorAs(t, err, &unknownAuthority)
+
+	_, err = LoginWithOptions(fakecc.Username, fakecc.Password, nil, fakeClientOptions(srv))
+	assert.ErrorAs(t, err, &unknownAuthority)
+}
+
+func TestLoginThroughProxy(t *testing.T) {
+	srv := newFakeServer(t)
+
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		r.RequestURI = ""
+		res, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer res.Body.Close()
+		for k, v := range res.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(res.StatusCode)
+		io.Copy(w, res.Body)
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(TransportOptions{ProxyURL: proxy.URL})
+	require.Nil(t, err)
+	opts := fakeClientOptions(srv)
+	opts.Transport = transport
+
+	client, err := LoginWithOptions(fakecc.Username, fakecc.Password, nil, opts)
+	require.Nil(t, err)
+	loginRequests := atomic.LoadInt32(&proxied)
+	assert.NotZero(t, loginRequests)
+
+	_, err = client.GetProductsSlice()
+	require.Nil(t, err)
+	assert.Greater(t, atomic.LoadInt32(&proxied), loginRequests)
+}
+
+func TestClientCertificate(t *testing.T) {
+	clientCert, certFile, keyFile := writeClientCert(t)
+
+	var presented int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			atomic.AddInt32(&presented, 1)
+		}
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	transport, err := NewTransport(TransportOptions{CAFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile})
+	require.Nil(t, err)
+
+	opts := ClientOptions{Endpoints: NewEndpoints(srv.URL, srv.URL), Transport: transport}
+	require.Nil(t, CheckConnectivityCtx(context.Background(), opts))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&presented))
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	_, err := NewTransport(TransportOptions{ProxyURL: "proxy.example.com"})
+	assert.ErrorIs(t, err, ErrorInvalidProxy)
+
+	_, err = NewTransport(TransportOptions{ClientCertFile: "client.pem"})
+	assert.ErrorIs(t, err, ErrorIncompleteCertKey)
+
+	_, err = NewTransport(TransportOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	require.Nil(t, os.WriteFile(invalid, []byte("not a certificate"), 0600))
+	_, err = NewTransport(TransportOptions{CAFile: invalid})
+	assert.ErrorIs(t, err, ErrorInvalidCABundle)
+}
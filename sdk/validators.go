@@ -4,7 +4,9 @@
 package sdk
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -13,18 +15,13 @@ var ErrorInvalidCategory = errors.New("api: category is not valid")
 var ErrorInvalidVersion = errors.New("api: version is not valid")
 var ErrorServerError = errors.New("api: server down. 500 error received")
 
-func (c *Client) validateSlugCategoryVersion(slug, category, majorVersion string) (err error) {
-	c.EnsureProductDetailMap()
-	if err != nil {
+func (c *Client) validateSlugCategoryVersion(ctx context.Context, slug, category, majorVersion string) (err error) {
+	var productDetails ProductDetails
+	if productDetails, err = c.lookupProduct(ctx, slug); err != nil {
 		return
 	}
 
-	if _, ok := ProductDetailMap[slug]; !ok {
-		err = ErrorInvalidSlug
-		return
-	}
-
-	if ProductDetailMap[slug].LatestMajorVersion != majorVersion {
+	if productDetails.LatestMajorVersion != majorVersion {
 		err = ErrorInvalidVersion
 		return
 	}
@@ -33,24 +30,39 @@ func (c *Client) validateSlugCategoryVersion(slug, category, majorVersion string
 	// Potential for circular dependency as this validator used by version get command
 }
 
-func (c *Client) validateResponseSlugCategoryVersion(slug, category, majorVersion string, res http.Response) (err error) {
+func (c *Client) validateResponseSlugCategoryVersion(ctx context.Context, slug, category, majorVersion string, res *http.Response) (err error) {
 	if res.StatusCode == 400 {
-		err = c.validateSlugCategoryVersion(slug, category, majorVersion)
+		if err = c.validateSlugCategoryVersion(ctx, slug, category, majorVersion); err != nil {
+			err = newAPIError(res, err)
+		}
 		return
 	}
 	return
 }
 
-func (c *Client) validateResponseGeneric(resCode int) (err error) {
-	if resCode == 401 {
-		err = ErrorNotAuthorized
+func (c *Client) validateResponseGeneric(res *http.Response) (err error) {
+	if res.StatusCode == 401 {
+		err = newAPIError(res, ErrorNotAuthorized)
 		return
-	} else if resCode == 500 {
-		err = ErrorServerError
+	} else if res.StatusCode == 500 {
+		err = newAPIError(res, ErrorServerError)
 		return
-	} else if resCode != 200 {
-		err = ErrorNon200Response
+	} else if res.StatusCode != 200 {
+		err = newAPIError(res, ErrorNon200Response)
 		return
 	}
 	return
 }
+
+// validateResponseNon200 is validateResponseGeneric for methods which returned ErrorNon200Response
+// for every unexpected status, so errors.Is keeps matching it alongside the more specific error.
+func (c *Client) validateResponseNon200(res *http.Response) (err error) {
+	if err = c.validateResponseGeneric(res); err == nil || errors.Is(err, ErrorNon200Response) {
+		return
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Err = fmt.Errorf("%w: %w", apiErr.Err, ErrorNon200Response)
+	}
+	return
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words which mark a version as coming before the release it is attached to
+var preReleaseWords = map[string]bool{"alpha": true, "beta": true, "rc": true, "tp": true}
+
+type versionToken struct {
+	numeric bool
+	value   string
+}
+
+// CompareVersions compares two VMware version names, returning -1 when a is older than b,
+// 1 when it is newer and 0 when they are equal.
+//
+// Numeric segments are compared as numbers, so 11.0 is newer than 9.0 and 8.0U10 is newer than 8.0U2.
+// Update markers are treated as separators, so 8.0U2 sorts alongside 8.0.2. Any other letters
+// denote a patch of the preceding release, e.g. 8.0U1 < 8.0U1a < 8.0U1c < 8.0U2 and 6.7 < 6.7P01 < 6.7U1.
+// Text before the first number, such as "ESXi " in "ESXi 8.0U2", is only used as a tie breaker.
+func CompareVersions(a, b string) int {
+	prefixA, tokensA := tokenizeVersion(a)
+	prefixB, tokensB := tokenizeVersion(b)
+
+	if result := compareVersionTokenSlices(tokensA, tokensB); result != 0 {
+		return result
+	}
+	if result := strings.Compare(prefixA, prefixB); result != 0 {
+		return result
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare the segments of two versions, ignoring any prefix
+func compareVersionTokenSlices(tokensA, tokensB []versionToken) int {
+	for i := 0; i < len(tokensA) && i < len(tokensB); i++ {
+		if result := compareVersionTokens(tokensA[i], tokensB[i]); result != 0 {
+			return result
+		}
+	}
+
+	// When one version has more segments it is the newer, unless they mark a pre-release
+	if len(tokensA) != len(tokensB) {
+		result := 1
+		extra := tokensA
+		if len(tokensB) > len(tokensA) {
+			result, extra = -1, tokensB
+		}
+		if next := extra[min(len(tokensA), len(tokensB))]; !next.numeric && preReleaseWords[next.value] {
+			result = -result
+		}
+		return result
+	}
+	return 0
+}
+
+func compareVersionTokens(a, b versionToken) int {
+	switch {
+	case a.numeric && b.numeric:
+		// Compare by length first to avoid overflowing on long build numbers
+		if len(a.value) != len(b.value) {
+			if len(a.value) < len(b.value) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.value, b.value)
+	case a.numeric:
+		return 1
+	case b.numeric:
+		return -1
+	}
+	return strings.Compare(a.value, b.value)
+}
+
+// Split a version into the text before the first number and its numeric and alphabetic segments
+func tokenizeVersion(version string) (prefix string, tokens []versionToken) {
+	start := strings.IndexFunc(version, unicode.IsDigit)
+	if start < 0 {
+		return strings.ToLower(strings.TrimSpace(version)), nil
+	}
+	prefix = strings.ToLower(strings.TrimSpace(version[:start]))
+
+	runes := []rune(strings.ToLower(version[start:]))
+	for i := 0; i < len(runes); {
+		j := i
+		switch {
+		case unicode.IsDigit(runes[i]):
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			value := strings.TrimLeft(string(runes[i:j]), "0")
+			if value == "" {
+				value = "0"
+			}
+			tokens = append(tokens, versionToken{numeric: true, value: value})
+		case unicode.IsLetter(runes[i]):
+			for j < len(runes) && unicode.IsLetter(runes[j]) {
+				j++
+			}
+			if value := string(runes[i:j]); value != "u" && value != "update" {
+				tokens = append(tokens, versionToken{value: value})
+			}
+		default:
+			j++
+		}
+		i = j
+	}
+	return
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	ascending := [][2]string{
+		{"9.0", "11.0"},
+		{"8.0U2", "8.0U10"},
+		{"8.0U1", "8.0U1a"},
+		{"8.0U1a", "8.0U1c"},
+		{"8.0U1c", "8.0U2"},
+		{"6.7", "6.7P01"},
+		{"6.7P01", "6.7U1"},
+		{"7.0U3", "8.0"},
+		{"ESXi 8.0U1", "ESXi 8.0.2"},
+		{"10.2.5", "10.3.25"},
+		{"2006", "2106"},
+		{"12.0.0 beta", "12.0.0"},
+		{"4.0.1.1", "4.0.1.1 LE"},
+		{"8.0.2", "8.0U2"},
+	}
+	for _, pair := range ascending {
+		assert.Equal(t, -1, CompareVersions(pair[0], pair[1]), "%s < %s", pair[0], pair[1])
+		assert.Equal(t, 1, CompareVersions(pair[1], pair[0]), "%s > %s", pair[1], pair[0])
+	}
+	assert.Equal(t, 0, CompareVersions("8.0U2", "8.0U2"))
+}
+
+func TestSortVersionMapKeys(t *testing.T) {
+	versionMap := map[string]APIVersions{}
+	for _, version := range []string{"9.0", "11.0", "8.0U2", "8.0U10", "8.0U1c", "8.0", "10.0"} {
+		versionMap[version] = APIVersions{}
+	}
+	assert.Equal(t, []string{"11.0", "10.0", "9.0", "8.0U10", "8.0U2", "8.0U1c", "8.0"}, sortVersionMapKeys(versionMap))
+}
+
+func TestFindVersionFromGlobSemantic(t *testing.T) {
+	versionMap := map[string]APIVersions{"9.0": {}, "11.0": {}, "8.0U2": {}, "8.0U10": {}}
+
+	version, err := basicClient.FindVersionFromGlob("*", versionMap)
+	assert.Nil(t, err)
+	assert.Equal(t, "11.0", version)
+
+	version, err = basicClient.FindVersionFromGlob("8.0U*", versionMap)
+	assert.Nil(t, err)
+	assert.Equal(t, "8.0U10", version)
+}
+
+func TestGetVersionSliceSorted(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	versions, err := client.GetVersionSlice("vmware_vsphere", "esxi", "PRODUCT_BINARY")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"8.0U2", "8.0U1c", "8.0U1", "8.0", "7.0U3c", "7.0U3", "7.0U2"}, versions)
+
+	versions, err = client.GetVersionSlice("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"12.3.0", "12.1.5", "11.3.5", "11.1.1", "11.1.0", "10.3.25", "10.2.5"}, versions)
+}
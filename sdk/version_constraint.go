@@ -0,0 +1,227 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var ErrorInvalidVersionConstraint = errors.New("versions: invalid version constraint")
+
+var constraintOperators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// VersionConstraint is a set of clauses which must all match a version. Clauses are separated
+// by commas, or by spaces when the next clause starts with an operator, and take the forms:
+//
+//	>=8.0 <8.1   comparisons using VMware version ordering, see CompareVersions
+//	=8.0U2       exact version, != excludes a version
+//	~8.0U2       the 8.0U2 release line, i.e. 8.0U2, 8.0U2a, 8.0U2b but not 8.0U3
+//	^7.0U3       7.0U3 or newer within the same major version
+//	8.0U*        glob on the version name
+//	latest       any version, the same as *
+//
+// Words without an operator continue the version of the clause before them, so "=ESXi 8.0U2"
+// is a single clause. Text before the first number of a version, e.g. "ESXi " in "ESXi 8.0U2",
+// is ignored when comparing.
+type VersionConstraint struct {
+	raw     string
+	clauses []versionClause
+}
+
+type versionClause struct {
+	operator string
+	operand  string
+	tokens   []versionToken
+}
+
+func ParseVersionConstraint(constraint string) (parsed VersionConstraint, err error) {
+	parsed.raw = constraint
+
+	var fields []string
+	for _, group := range strings.Split(constraint, ",") {
+		words := strings.Fields(group)
+		groupStart := len(fields)
+		for i := 0; i < len(words); i++ {
+			word := words[i]
+			switch {
+			case isConstraintOperator(word) && i+1 < len(words):
+				// Allow a space between the operator and the version, e.g. ">= 8.0"
+				i++
+				word += words[i]
+			case len(fields) > groupStart && !startsVersionClause(word):
+				// Versions may contain spaces, e.g. "ESXi 8.0U2"
+				fields[len(fields)-1] += " " + word
+				continue
+			}
+			fields = append(fields, word)
+		}
+	}
+
+	for _, field := range fields {
+		var clause versionClause
+		if clause, err = parseVersionClause(field); err != nil {
+			return
+		}
+		parsed.clauses = append(parsed.clauses, clause)
+	}
+
+	if len(parsed.clauses) == 0 {
+		err = fmt.Errorf("%w: %q is empty", ErrorInvalidVersionConstraint, constraint)
+	}
+	return
+}
+
+func isConstraintOperator(field string) bool {
+	for _, operator := range constraintOperators {
+		if field == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func startsVersionClause(word string) bool {
+	if word == "latest" || word == "*" {
+		return true
+	}
+	for _, operator := range constraintOperators {
+		if strings.HasPrefix(word, operator) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersionClause(field string) (clause versionClause, err error) {
+	if field == "latest" || field == "*" {
+		clause.operator = "*"
+		return
+	}
+
+	for _, operator := range constraintOperators {
+		if strings.HasPrefix(field, operator) {
+			clause.operator = operator
+			clause.operand = strings.TrimPrefix(field, operator)
+			break
+		}
+	}
+	if clause.operator == "" {
+		clause.operator, clause.operand = "=", field
+		if strings.Contains(field, "*") {
+			clause.operator = "glob"
+		}
+	}
+	if clause.operator == "==" {
+		clause.operator = "="
+	}
+
+	_, clause.tokens = tokenizeVersion(clause.operand)
+	if clause.operand == "" || (clause.operator != "glob" && clause.operator != "=" && clause.operator != "!=" && len(clause.tokens) == 0) {
+		err = fmt.Errorf("%w: %q", ErrorInvalidVersionConstraint, field)
+	}
+	return
+}
+
+func (v VersionConstraint) String() string {
+	return v.raw
+}
+
+// Matches reports whether version satisfies every clause of the constraint
+func (v VersionConstraint) Matches(version string) bool {
+	_, tokens := tokenizeVersion(version)
+	for _, clause := range v.clauses {
+		if !clause.matches(version, tokens) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c versionClause) matches(version string, tokens []versionToken) bool {
+	switch c.operator {
+	case "*":
+		return true
+	case "glob":
+		match, _ := path.Match(c.operand, version)
+		return match
+	case "=":
+		return version == c.operand
+	case "!=":
+		return version != c.operand
+	case "~":
+		return hasVersionPrefix(tokens, c.tokens)
+	case "^":
+		return len(tokens) > 0 && tokens[0] == c.tokens[0] && compareVersionTokenSlices(tokens, c.tokens) >= 0
+	}
+
+	result := compareVersionTokenSlices(tokens, c.tokens)
+	switch c.operator {
+	case ">=":
+		return result >= 0
+	case ">":
+		return result > 0
+	case "<=":
+		return result <= 0
+	case "<":
+		return result < 0
+	}
+	return false
+}
+
+func hasVersionPrefix(tokens, prefix []versionToken) bool {
+	if len(tokens) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if tokens[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FindVersionMatching resolves a version constraint against the versions of a sub-product.
+// The newest matching version is returned along with all candidates, sorted newest first.
+func (c *Client) FindVersionMatching(slug, subProduct, constraint, dlgType string) (best APIVersions, candidates []APIVersions, err error) {
+	return c.FindVersionMatchingCtx(context.Background(), slug, subProduct, constraint, dlgType)
+}
+
+func (c *Client) FindVersionMatchingCtx(ctx context.Context, slug, subProduct, constraint, dlgType string) (best APIVersions, candidates []APIVersions, err error) {
+	ctx, span := c.startSpan(ctx, "FindVersionMatching", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrVersion, constraint), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
+	var parsed VersionConstraint
+	if parsed, err = ParseVersionConstraint(constraint); err != nil {
+		return
+	}
+
+	var versionMap map[string]APIVersions
+	versionMap, err = c.GetVersionMapCtx(ctx, slug, subProduct, dlgType)
+	if err != nil {
+		return
+	}
+
+	candidates = matchVersionConstraint(parsed, versionMap)
+	if len(candidates) == 0 {
+		err = ErrorNoMatchingVersions
+		return
+	}
+
+	best = candidates[0]
+	return
+}
+
+func matchVersionConstraint(constraint VersionConstraint, versionMap map[string]APIVersions) (candidates []APIVersions) {
+	for _, version := range sortVersionMapKeys(versionMap) {
+		if constraint.Matches(version) {
+			apiVersions := versionMap[version]
+			apiVersions.MinorVersion = version
+			candidates = append(candidates, apiVersions)
+		}
+	}
+	return
+}
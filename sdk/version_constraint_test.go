@@ -0,0 +1,97 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: Apache 2.0
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraintMatches(t *testing.T) {
+	versions := []string{"8.0U10", "8.0U2b", "8.0U2a", "8.0U2", "8.0U1", "8.0", "7.0U3c", "7.0U3", "6.7"}
+
+	cases := map[string][]string{
+		">=8.0 <8.1":      {"8.0U10", "8.0U2b", "8.0U2a", "8.0U2", "8.0U1", "8.0"},
+		">= 7.0U3, <8":    {"7.0U3c", "7.0U3"},
+		"~8.0U2":          {"8.0U2b", "8.0U2a", "8.0U2"},
+		"~7.0":            {"7.0U3c", "7.0U3"},
+		"^7.0U3":          {"7.0U3c", "7.0U3"},
+		"=8.0U1":          {"8.0U1"},
+		"8.0U1":           {"8.0U1"},
+		"8.0U2*":          {"8.0U2b", "8.0U2a", "8.0U2"},
+		"~8.0U2 !=8.0U2b": {"8.0U2a", "8.0U2"},
+		"latest":          versions,
+		">9":              nil,
+	}
+
+	for constraint, expected := range cases {
+		parsed, err := ParseVersionConstraint(constraint)
+		require.Nil(t, err, constraint)
+
+		var matched []string
+		for _, version := range versions {
+			if parsed.Matches(version) {
+				matched = append(matched, version)
+			}
+		}
+		assert.Equal(t, expected, matched, constraint)
+	}
+}
+
+func TestVersionConstraintPrefix(t *testing.T) {
+	parsed, err := ParseVersionConstraint("<=8.0U2")
+	require.Nil(t, err)
+	assert.True(t, parsed.Matches("ESXi 8.0U2"))
+	assert.False(t, parsed.Matches("ESXi 8.0U3"))
+}
+
+func TestVersionConstraintSpaces(t *testing.T) {
+	parsed, err := ParseVersionConstraint("=ESXi 8.0U2")
+	require.Nil(t, err)
+	assert.True(t, parsed.Matches("ESXi 8.0U2"))
+	assert.False(t, parsed.Matches("8.0U2"))
+
+	parsed, err = ParseVersionConstraint("!=4.0.1.1 LE")
+	require.Nil(t, err)
+	assert.False(t, parsed.Matches("4.0.1.1 LE"))
+	assert.True(t, parsed.Matches("4.0.1.1"))
+
+	parsed, err = ParseVersionConstraint(">= ESXi 8.0, !=ESXi 8.0U2 <ESXi 8.1")
+	require.Nil(t, err)
+	assert.True(t, parsed.Matches("ESXi 8.0U1"))
+	assert.False(t, parsed.Matches("ESXi 8.0U2"))
+	assert.False(t, parsed.Matches("ESXi 8.1"))
+}
+
+func TestParseVersionConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", ">=", "~abc", " , "} {
+		_, err := ParseVersionConstraint(constraint)
+		assert.ErrorIs(t, err, ErrorInvalidVersionConstraint, constraint)
+	}
+}
+
+func TestFindVersionMatching(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(fakeClientOptions(srv))
+
+	best, candidates, err := client.FindVersionMatching("vmware_vsphere", "esxi", "~8.0U1", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Equal(t, "8.0U1c", best.MinorVersion)
+	assert.Equal(t, "ESXI80U1C", best.Code)
+	assert.Equal(t, "8_0", best.MajorVersion)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "8.0U1", candidates[1].MinorVersion)
+
+	best, _, err = client.FindVersionMatching("vmware_tools", "vmtools", ">=11 <12", "PRODUCT_BINARY")
+	require.Nil(t, err)
+	assert.Equal(t, "11.3.5", best.MinorVersion)
+
+	_, _, err = client.FindVersionMatching("vmware_tools", "vmtools", ">=13", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, ErrorNoMatchingVersions)
+
+	_, _, err = client.FindVersionMatching("vmware_tools", "vmtools", ">=", "PRODUCT_BINARY")
+	assert.ErrorIs(t, err, ErrorInvalidVersionConstraint)
+}
@@ -4,6 +4,7 @@
 package sdk
 
 import (
+	"context"
 	"errors"
 	"sort"
 	"strings"
@@ -20,18 +21,27 @@ var ErrorNoVersinGlob = errors.New("versions: invalid glob. glob my be provided"
 var ErrorMultipleVersionGlob = errors.New("versions: invalid glob. a single version glob must be used")
 
 func (c *Client) GetVersionMap(slug, subProductName, dlgType string) (data map[string]APIVersions, err error) {
+	return c.GetVersionMapCtx(context.Background(), slug, subProductName, dlgType)
+}
+
+func (c *Client) GetVersionMapCtx(ctx context.Context, slug, subProductName, dlgType string) (data map[string]APIVersions, err error) {
+	ctx, span := c.startSpan(ctx, "GetVersionMap", attr(AttrSlug, slug), attr(AttrSubProduct, subProductName), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	data = make(map[string]APIVersions)
 
 	var subProductDetails  SubProductDetails
-	subProductDetails, err = c.GetSubProduct(slug, subProductName, dlgType)
+	subProductDetails, err = c.GetSubProductCtx(ctx, slug, subProductName, dlgType)
 	if err != nil {
 		return
 	}
+	if c.snapshot != nil {
+		return c.snapshot.versions(slug, subProductName, dlgType)
+	}
 
 	// Loop through each major version and collect all versions
 	for majorVersion, dlgList := range subProductDetails.DlgListByVersion {
 		var dlgHeader DlgHeader
-		dlgHeader, err = c.GetDlgHeader(dlgList.Code, dlgList.ProductID)
+		dlgHeader, err = c.GetDlgHeaderCtx(ctx, dlgList.Code, dlgList.ProductID)
 		if err != nil {
 			return
 		}
@@ -54,8 +64,14 @@ func (c *Client) GetVersionMap(slug, subProductName, dlgType string) (data map[s
 }
 
 func (c *Client) FindVersion(slug, subProduct, version, dlgType string) (data APIVersions, err error) {
+	return c.FindVersionCtx(context.Background(), slug, subProduct, version, dlgType)
+}
+
+func (c *Client) FindVersionCtx(ctx context.Context, slug, subProduct, version, dlgType string) (data APIVersions, err error) {
+	ctx, span := c.startSpan(ctx, "FindVersion", attr(AttrSlug, slug), attr(AttrSubProduct, subProduct), attr(AttrVersion, version), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var versionMap map[string]APIVersions
-	versionMap, err = c.GetVersionMap(slug, subProduct, dlgType)
+	versionMap, err = c.GetVersionMapCtx(ctx, slug, subProduct, dlgType)
 	if err != nil {
 		return
 	}
@@ -99,8 +115,10 @@ func (c *Client) FindVersionFromGlob(versionGlob string, versionMap map[string]A
 	// Check if only * is provided as strings. Split returns empty if separator is found.
 	if versionPrefix == "" {
 		// return the first entry, which is the highest number.
-		version = sortedKeys[0]
-		return
+		if len(sortedKeys) > 0 {
+			version = sortedKeys[0]
+			return
+		}
 	} else {
 		// return the first entry matching the prefix
 		for _, key := range sortedKeys {
@@ -116,8 +134,14 @@ func (c *Client) FindVersionFromGlob(versionGlob string, versionMap map[string]A
 }
 
 func (c *Client) GetVersionSlice(slug, subProductName, dlgType string) (data []string, err error) {
+	return c.GetVersionSliceCtx(context.Background(), slug, subProductName, dlgType)
+}
+
+func (c *Client) GetVersionSliceCtx(ctx context.Context, slug, subProductName, dlgType string) (data []string, err error) {
+	ctx, span := c.startSpan(ctx, "GetVersionSlice", attr(AttrSlug, slug), attr(AttrSubProduct, subProductName), attr(AttrDlgType, dlgType))
+	defer func() { endSpan(span, err) }()
 	var versionMap map[string]APIVersions
-	versionMap, err = c.GetVersionMap(slug, subProductName, dlgType)
+	versionMap, err = c.GetVersionMapCtx(ctx, slug, subProductName, dlgType)
 	if err != nil {
 		return
 	}
@@ -128,14 +152,16 @@ func (c *Client) GetVersionSlice(slug, subProductName, dlgType string) (data []s
 }
 
 func sortVersionMapKeys(versionMap map[string]APIVersions) (keys []string) {
-	// Extract all keys which are the version strings and reverse sort them
-	// This means the versions will go from high to low
+	// Extract all keys which are the version strings and sort them newest first
+	// using VMware version ordering, see CompareVersions
 	keys = make([]string, len(versionMap))
 	i := 0
 	for key := range versionMap {
 		keys[i] = key
 		i++
 	}
-	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	sort.SliceStable(keys, func(i, j int) bool {
+		return CompareVersions(keys[i], keys[j]) > 0
+	})
 	return
 }
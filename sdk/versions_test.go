@@ -10,6 +10,8 @@ import (
 )
 
 func TestGetVersionSuccess(t *testing.T) {
+	skipUnlessLive(t)
+
 	var versions map[string]APIVersions
 	versions, err = basicClient.GetVersionMap("vmware_horizon_clients", "cart+win", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -19,6 +21,8 @@ func TestGetVersionSuccess(t *testing.T) {
 }
 
 func TestGetVersionSuccessHorizon(t *testing.T) {
+	skipUnlessLive(t)
+
 	var versions map[string]APIVersions
 	versions, err = basicClient.GetVersionMap("vmware_horizon", "dem+standard", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -28,6 +32,8 @@ func TestGetVersionSuccessHorizon(t *testing.T) {
 }
 
 func TestGetVersionSuccessNsxLe(t *testing.T) {
+	skipUnlessLive(t)
+
 	var versions map[string]APIVersions
 	versions, err = basicClient.GetVersionMap("vmware_nsx", "nsx_le", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -37,6 +43,8 @@ func TestGetVersionSuccessNsxLe(t *testing.T) {
 }
 
 func TestGetVersionSuccessNsx(t *testing.T) {
+	skipUnlessLive(t)
+
 	var versions map[string]APIVersions
 	versions, err = basicClient.GetVersionMap("vmware_nsx", "nsx", "PRODUCT_BINARY")
 	assert.Nil(t, err)
@@ -46,68 +54,87 @@ func TestGetVersionSuccessNsx(t *testing.T) {
 }
 
 func TestGetVersionMapInvalidSubProduct(t *testing.T) {
+	client := newFakeClient(t)
 	var versions map[string]APIVersions
-	versions, err = basicClient.GetVersionMap("vmware_tools", "dummy", "PRODUCT_BINARY")
+	versions, err = client.GetVersionMap("vmware_tools", "dummy", "PRODUCT_BINARY")
 	assert.ErrorIs(t, err, ErrorInvalidSubProduct)
 	assert.Empty(t, versions, "Expected response to be empty")
 }
 
 func TestGetVersionInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var versions map[string]APIVersions
-	versions, err = basicClient.GetVersionMap("mware_tools", "vmtools", "PRODUCT_BINARY")
+	versions, err = client.GetVersionMap("mware_tools", "vmtools", "PRODUCT_BINARY")
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, versions, "Expected response to be empty")
 }
 
 func TestFindVersion(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("vmware_tools", "vmtools", "11.1.1", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("vmware_tools", "vmtools", "11.1.1", "PRODUCT_BINARY")
 	assert.Nil(t, err)
 	assert.NotEmpty(t, foundVersion.Code, "Expected response not to be empty")
 	assert.Equal(t, foundVersion.MinorVersion, "11.1.1")
 }
 
 func TestFindVersionInvalidSlug(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("mware_tools", "vmtools", "11.1.1", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("mware_tools", "vmtools", "11.1.1", "PRODUCT_BINARY")
 	assert.ErrorIs(t, err, ErrorInvalidSlug)
 	assert.Empty(t, foundVersion.Code, "Expected response to be empty")
 }
 
 func TestFindVersionInvalidVersion(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("vmware_tools", "vmtools", "666", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("vmware_tools", "vmtools", "666", "PRODUCT_BINARY")
 	assert.ErrorIs(t, err, ErrorInvalidVersion)
 	assert.Empty(t, foundVersion.Code, "Expected response to be empty")
 }
 
 func TestFindVersionInvalidSubProduct(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("vmware_tools", "tools", "11.1.1", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("vmware_tools", "tools", "11.1.1", "PRODUCT_BINARY")
 	assert.ErrorIs(t, err, ErrorInvalidSubProduct)
 	assert.Empty(t, foundVersion.Code, "Expected response to be empty")
 
 }
 
 func TestFindVersionMinorGlob(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("vmware_tools", "vmtools", "10.2.*", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("vmware_tools", "vmtools", "10.2.*", "PRODUCT_BINARY")
 	assert.Nil(t, err)
-	assert.Equal(t, foundVersion.Code, "VMTOOLS1021")
+	assert.Equal(t, foundVersion.Code, "VMTOOLS1025")
 	assert.Contains(t, foundVersion.MinorVersion, "10.2")
 }
 
 func TestFindVersionOnlyGlob(t *testing.T) {
+	client := newFakeClient(t)
 	var foundVersion APIVersions
-	foundVersion, err = basicClient.FindVersion("vmware_tools", "vmtools", "*", "PRODUCT_BINARY")
+	foundVersion, err = client.FindVersion("vmware_tools", "vmtools", "*", "PRODUCT_BINARY")
 	assert.Nil(t, err)
 	assert.NotEmpty(t, foundVersion.Code)
 	assert.Contains(t, foundVersion.MinorVersion, ".")
 }
 
+func TestFindVersionFromGlobEmptyMap(t *testing.T) {
+	client := NewClient(ClientOptions{})
+	for _, glob := range []string{"*", "11.*"} {
+		version, err := client.FindVersionFromGlob(glob, map[string]APIVersions{})
+		assert.ErrorIs(t, err, ErrorNoMatchingVersions)
+		assert.Empty(t, version)
+	}
+}
+
 func TestGetVersionArraySuccess(t *testing.T) {
+	client := newFakeClient(t)
 	var versions []string
-	versions, err = basicClient.GetVersionSlice("vmware_tools", "vmtools", "PRODUCT_BINARY")
+	versions, err = client.GetVersionSlice("vmware_tools", "vmtools", "PRODUCT_BINARY")
 	assert.Nil(t, err)
-	assert.Greater(t, len(versions), 10, "Expected response to contain at least 10 items")
+	assert.Len(t, versions, 7)
+	assert.Contains(t, versions, "11.3.5")
 }